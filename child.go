@@ -0,0 +1,65 @@
+package gogroup
+
+import (
+	"context"
+	"runtime"
+)
+
+type ChildPolicy int
+
+const (
+	ChildCancelParent ChildPolicy = iota // default. exit of child group cancels parent group
+	ChildIsolated                        // exit of child group only cancels itself
+)
+
+// NewChild returns a child Group whose ctx derives from g's ctx, so canceling g cancels the child.
+// once the child starts its first goroutine, g waits for it, and its ExitInfo is embedded in ExitInfo().Children of g.
+// whether exit of the child cancels g is decided by policy
+func (g *Group) NewChild(policy ChildPolicy) *Group {
+	g.init()
+	c := &Group{groupBase: groupBase{root: g.ctx}, parent: g, childPolicy: policy}
+	c.init()
+	c.childFi = FuncInfo{Description: "child group"}
+	if pc, file, line, ok := runtime.Caller(1); ok {
+		c.childFi.File, c.childFi.Line = file, line
+		if f := runtime.FuncForPC(pc); f != nil {
+			c.childFi.FuncName = f.Name()
+		}
+	}
+	return c
+}
+
+// superviseChild starts a goroutine in g who waits c exit
+func (g *Group) superviseChild(c *Group) {
+	if g.state.Load() == groupStateExited {
+		return
+	}
+	spec := goSpec{fi: c.childFi, optional: c.childPolicy == ChildIsolated, ignorePanic: true, unlimited: true}
+	g.goWithSpec(func(ctx context.Context) error {
+		c.waitExit(g.waitingC)
+		ei := c.exitInfo()
+		g.exitsM.Lock()
+		g.children = append(g.children, ei)
+		g.exitsM.Unlock()
+		if c.childPolicy == ChildIsolated {
+			return nil
+		}
+		return ei.Cause
+	}, spec)
+}
+
+// waitExit waits child c exit without f5, so c still accepts Go after its goroutines exited, like its parent.
+// once parentWaiting is closed, c is canceled when no goroutine is running, as if f5 of c is called
+func (c *Group) waitExit(parentWaiting <-chan struct{}) {
+	for {
+		select {
+		case <-c.exitedChan():
+			if c.exitFinished() { // c may be reopened by Go
+				return
+			}
+		case <-parentWaiting:
+			c.enterWaiting()
+			parentWaiting = nil
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupNewChildCancelParent(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	c := g.NewChild(ChildCancelParent)
+	c.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	c.Go(func(ctx context.Context) {
+		panic("child")
+	})
+	ei := g.ExitInfo()
+	var pe *PanicError
+	if !errors.As(g.Err(), &pe) || pe.Value != "child" {
+		t.Fatal("parent should be canceled by panic of child", g.Err())
+	}
+	if len(ei.Children) != 1 || len(ei.Children[0].GoInfos) != 2 {
+		t.Fatal("Children not right", ei)
+	}
+	if !strings.Contains(ei.GoInfos[0].FuncInfo.String(), "(child group)") {
+		t.Fatal("GoInfo of child not right", ei.GoInfos[0].FuncInfo)
+	}
+	s := ei.String()
+	if !strings.Contains(s, "==Child 1==\n    ====Group ExitInfo====\n") {
+		t.Fatal("String not indented\n", s)
+	}
+	fmt.Println(s)
+}
+
+func TestGroupNewChildIsolated(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	c := g.NewChild(ChildIsolated)
+	c.Go(func(ctx context.Context) {})
+	c.Wait()
+	time.Sleep(time.Millisecond * 10)
+	if isContextDone(g.ctx) {
+		t.Fatal("isolated child should not cancel parent", context.Cause(g.ctx))
+	}
+	g.CancelAndWait(fmt.Errorf("stop"))
+	if ei := g.ExitInfo(); len(ei.Children) != 1 || !ei.CancelByUser {
+		t.Fatal("ExitInfo not right", ei)
+	}
+}
+
+func TestGroupNewChildCanceledByParent(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	c := g.NewChild(ChildCancelParent)
+	c.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.CancelAndWait(fmt.Errorf("stop"))
+	if !c.ExitInfo().CancelByRootContext {
+		t.Fatal("child should be canceled by root context")
+	}
+	if g.Err().Error() != "stop" {
+		t.Fatal("err not stop", g.Err())
+	}
+}
+
+func TestGroupNewChildOptional(t *testing.T) {
+	var g Group
+	c := g.NewChild(ChildCancelParent)
+	c.GoOptional(func(ctx context.Context) {}, false) // warm-up
+	time.Sleep(time.Millisecond * 10)
+	c.Go(func(ctx context.Context) { // server
+		<-ctx.Done()
+	})
+	if isContextDone(c.ctx) || isContextDone(g.ctx) {
+		t.Fatal("exit of optional goroutine should not end the child")
+	}
+	g.CancelAndWait(errSentinel)
+	ei := g.ExitInfo()
+	if len(ei.Children) != 1 || len(ei.Children[0].GoInfos) != 2 {
+		t.Fatal("Children not right", ei)
+	}
+}
+
+func TestGroupNewChildOptionalParentWait(t *testing.T) {
+	var g Group
+	c := g.NewChild(ChildIsolated)
+	c.GoOptional(func(ctx context.Context) {
+		time.Sleep(time.Millisecond * 10)
+	}, false)
+	done := make(chan struct{})
+	go func() {
+		g.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Wait of parent should end the child with only optional goroutines")
+	}
+	if len(g.ExitInfo().Children) != 1 || !isContextDone(c.ctx) {
+		t.Fatal("child should be canceled when idle", c.exitInfo())
+	}
+}
@@ -3,11 +3,13 @@ package gogroup
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -69,12 +71,48 @@ type GoGroup interface {
 	// f5 will block until GoGroup exited
 }
 
+// GoGroupExt is GoGroup with more ways to start goroutines. Group and MiniGroup implement it.
+// it is not merged into GoGroup, so that types implementing GoGroup outside gogroup are not broken
+type GoGroupExt interface {
+	GoGroup
+
+	// GoOptional start a non-critical goroutine in GoGroup
+	// normal return of f won't cancel GoGroup, panic of f won't either if ignorePanic is true
+	// Wait still waits for it. once f5 is called and no goroutine is running, GoGroup is canceled
+	// (with ErrAllExited if none was running when f5 is called), so Wait won't block forever
+	// usually a one-shot task, such as a cache warm-up
+	GoOptional(f func(context.Context), ignorePanic bool)
+
+	// GoErr same as Go, but f returns an error
+	// non-nil error returned by f becomes the cause of GoGroup, errors.Is and errors.As can reach it
+	// nil is treated as a normal exit
+	GoErr(f func(context.Context) error)
+
+	// GoTkErr same as GoTk, but tkf returns an error
+	// the goroutine exits when tkf returns a non-nil error, see GoErr
+	GoTkErr(tkf func() error, d time.Duration)
+
+	// SetLimit limits the number of goroutines running in GoGroup to at most n.
+	// Go blocks until a goroutine exits when the limit is reached. a negative n means no limit (default).
+	// should be called before Go, it panics if goroutines are running
+	SetLimit(n int)
+
+	// TryGo same as Go, but returns false without starting f if the limit is reached. see SetLimit
+	TryGo(f func(context.Context)) bool
+}
+
 type GoInfo struct {
-	FuncInfo   FuncInfo
-	Panic      any
-	PanicStack []byte
-	ExitTime   time.Time
-	StartTime  time.Time
+	FuncInfo    FuncInfo
+	Panic       any
+	PanicStack  []byte  // text form of PanicFrames
+	PanicFrames []Frame // stack of the panic, without frames of gogroup and runtime
+	ExitTime    time.Time
+	StartTime   time.Time
+	Attempt     int           // 1 for the first run, increases on each restart. see RestartPolicy
+	Optional    bool          // started by GoOptional
+	Err         error         // error returned by the goroutine, see GoErr
+	QueueWait   time.Duration // time waited for the limit before start, see SetLimit
+	Interval    time.Duration // interval of GoTk, 0 for Go
 }
 
 type FuncInfo struct {
@@ -92,9 +130,11 @@ type ExitInfo struct {
 	Cause                error
 	FirstUseLine         string
 	FirstUseTime         time.Time
-	CancelTime           *time.Time // maybe nil
-	FirstGoTime          *time.Time // maybe nil
-	ExitTime             *time.Time // maybe nil
+	CancelTime           *time.Time  // maybe nil
+	FirstGoTime          *time.Time  // maybe nil
+	ExitTime             *time.Time  // maybe nil
+	Phases               []PhaseInfo // nil if no goroutine started by GoWithPhase
+	Children             []*ExitInfo // ExitInfo of child groups, see Group.NewChild
 }
 
 func (ei ExitInfo) String() string {
@@ -135,6 +175,10 @@ func (ei ExitInfo) String() string {
 			builder.WriteString(", UnknownCanceler\n")
 		}
 	}
+	for _, pi := range ei.Phases {
+		builder.WriteString(pi.String())
+		builder.WriteByte('\n')
+	}
 	if len(ei.GoInfos) == 0 {
 		builder.WriteString("No GoInfo\n")
 		return builder.String()
@@ -155,7 +199,21 @@ func (ei ExitInfo) String() string {
 		builder.WriteString(gei.StartTime.Format(microsecondDate))
 		builder.WriteString(", ExitTime: ")
 		builder.WriteString(gei.ExitTime.Format(microsecondDate))
+		if gei.Attempt > 1 {
+			builder.WriteString(", Attempt: ")
+			builder.WriteString(strconv.Itoa(gei.Attempt))
+		}
+		if gei.Optional {
+			builder.WriteString(", Optional")
+		}
+		if gei.QueueWait > 0 {
+			builder.WriteString(", QueueWait: ")
+			builder.WriteString(gei.QueueWait.String())
+		}
 		builder.WriteByte('\n')
+		if gei.Err != nil {
+			builder.WriteString("Err: " + gei.Err.Error() + "\n")
+		}
 		if gei.Panic == nil {
 			continue
 		}
@@ -166,7 +224,21 @@ func (ei ExitInfo) String() string {
 		} else {
 			builder.Write(gei.PanicStack)
 		}
-		if i < len(ei.GoInfos)-1 {
+		if i < len(ei.GoInfos)-1 || len(ei.Children) > 0 {
+			builder.WriteByte('\n')
+		}
+	}
+	for i, child := range ei.Children {
+		builder.WriteString("==Child ")
+		builder.WriteString(strconv.Itoa(i + 1))
+		builder.WriteString("==\n")
+		for _, line := range strings.SplitAfter(child.String(), "\n") {
+			if line != "" {
+				builder.WriteString("    ")
+				builder.WriteString(line)
+			}
+		}
+		if i < len(ei.Children)-1 {
 			builder.WriteByte('\n')
 		}
 	}
@@ -215,11 +287,166 @@ func (w *watcher) watch() context.Context {
 	return w.watchCtx
 }
 
+// liveGo is a running goroutine in group
+type liveGo struct {
+	fi          FuncInfo
+	goroutineID int64
+	startTime   time.Time // start time of current attempt
+	attempt     int
+	interval    time.Duration
+	phase       int
+}
+
+// goSpec describes how a goroutine runs in group
+type goSpec struct {
+	fi          FuncInfo
+	restart     RestartPolicy
+	optional    bool          // normal exit won't cancel group
+	ignorePanic bool          // with optional, panic won't cancel group either
+	phase       int           // shutdown phase, see Group.GoWithPhase
+	unlimited   bool          // not counted by SetLimit, for goroutines started by gogroup itself
+	acquired    bool          // slot of SetLimit is acquired by TryGo
+	sem         chan struct{} // where the slot of SetLimit is acquired, nil if not limited
+	panicPolicy *PanicPolicy  // nil means PanicPolicy of group
+	interval    time.Duration // interval of GoTk, 0 for Go
+}
+
+// cancelOnExit reports whether the exit described by ei should cancel group
+func (s goSpec) cancelOnExit(ei GoInfo) bool {
+	if !s.optional || ei.Err != nil {
+		return true
+	}
+	return ei.Panic != nil && !s.ignorePanic
+}
+
 type groupBase struct {
 	root        context.Context
 	ctx         context.Context
 	cancelCause context.CancelCauseFunc
 	initOnce    sync.Once
+	semM        sync.Mutex
+	sem         chan struct{} // nil if no limit, guarded by semM
+	logger      atomic.Pointer[slog.Logger]
+	panicPolicy atomic.Pointer[PanicPolicy]
+	hooksM      sync.Mutex
+	hooks       []Hooks // copy on write
+	name        atomic.Pointer[string]
+	waiting     atomic.Bool // f5 is called, the group is canceled once no goroutine is running
+	exitNotifyM sync.Mutex
+	exitNotify  chan struct{}  // see exitChan
+	exitM       sync.Mutex     // orders exit without f5 with Go who reopens the group, see markExited
+	exitDone    atomic.Bool    // set by finishExit before moving group to History, written under exitM
+	exiting     sync.WaitGroup // finishExit after markExited, f5 waits it so that OnGroupExit is called before f5 returns
+	exitedC     chan struct{}  // closed by markExited, replaced when group is reopened. guarded by exitM, see exitedChan
+	exitCount   atomic.Int64   // see Stats
+	panicCount  atomic.Int64
+}
+
+func (g *groupBase) setLimit(n int) {
+	g.semM.Lock()
+	defer g.semM.Unlock()
+	if len(g.sem) != 0 {
+		panic(fmt.Errorf("gogroup: modify limit while %v goroutines in the group are still active", len(g.sem)))
+	}
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+func (g *groupBase) getSem() chan struct{} {
+	g.semM.Lock()
+	defer g.semM.Unlock()
+	return g.sem
+}
+
+// acquire blocks until a goroutine can be started, and records the slot in spec. returns time waited
+func (g *groupBase) acquire(spec *goSpec) time.Duration {
+	if spec.unlimited || spec.acquired {
+		return 0
+	}
+	sem := g.getSem()
+	if sem == nil {
+		return 0
+	}
+	spec.sem = sem
+	select {
+	case sem <- struct{}{}:
+		return 0
+	default:
+	}
+	start := time.Now()
+	sem <- struct{}{}
+	return time.Since(start)
+}
+
+// tryAcquire returns where the slot is acquired, it should be set to goSpec.sem with goSpec.acquired
+func (g *groupBase) tryAcquire() (chan struct{}, bool) {
+	sem := g.getSem()
+	if sem == nil {
+		return nil, true
+	}
+	select {
+	case sem <- struct{}{}:
+		return sem, true
+	default:
+		return nil, false
+	}
+}
+
+func (g *groupBase) release(spec goSpec) {
+	if spec.sem != nil {
+		<-spec.sem
+	}
+}
+
+// exitFinished returns true if group exited, even if f5 is not called. see Register
+func (g *groupBase) exitFinished() bool {
+	return g.exitDone.Load()
+}
+
+// markExited marks group exited and calls exited under exitM. it returns false if group is exited already,
+// or auto is true (f5 is not called) and a goroutine is running again.
+// if it returns true, exiting.Done must be called after the rest of finishExit
+func (g *groupBase) markExited(running *atomic.Int32, auto bool, exited func()) bool {
+	g.exitM.Lock()
+	defer g.exitM.Unlock()
+	if g.exitDone.Load() || auto && running.Load() != 0 {
+		return false
+	}
+	exited()
+	g.exitDone.Store(true)
+	if g.exitedC == nil {
+		g.exitedC = make(chan struct{})
+	}
+	close(g.exitedC)
+	g.exiting.Add(1)
+	return true
+}
+
+// exitedChan returns a channel who is closed when group exited, even if f5 is not called.
+// group may be reopened after it is closed, check exitFinished then
+func (g *groupBase) exitedChan() chan struct{} {
+	g.exitM.Lock()
+	defer g.exitM.Unlock()
+	if g.exitedC == nil {
+		g.exitedC = make(chan struct{})
+	}
+	return g.exitedC
+}
+
+// addRunning counts a new goroutine of group. if group exited without f5,
+// it is reopened by reopen first, so that the next exit is finished again
+func (g *groupBase) addRunning(running *atomic.Int32, reopen func()) {
+	g.exitM.Lock()
+	defer g.exitM.Unlock()
+	if g.exitDone.Load() {
+		reopen()
+		g.exitDone.Store(false)
+		g.exitedC = nil
+	}
+	running.Add(1)
 }
 
 func (g *groupBase) initBase() {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -92,7 +93,6 @@ func processOnce() {
 func processPanic(ctx context.Context) {
 	time.Sleep(time.Second * 1 / 20)
 	panic(111)
-	return
 }
 func funcCanPanic(ctx context.Context) {
 	panic("xsdd")
@@ -200,3 +200,247 @@ func testGoAfterWait(t *testing.T, a GoGroup) {
 	}
 
 }
+
+func testGoOptional(t *testing.T, g GoGroupExt) {
+	var warmed atomic.Bool
+	g.GoOptional(func(ctx context.Context) {
+		warmed.Store(true)
+	}, false)
+	g.GoOptional(func(ctx context.Context) {
+		panic("ignored")
+	}, true)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	time.Sleep(time.Millisecond * 50)
+	if isContextDone(g.Watch()) {
+		t.Fatal("optional goroutine exit should not cancel group")
+	}
+	g.CancelAndWait(fmt.Errorf("xxx"))
+	if !warmed.Load() {
+		t.Fatal("optional goroutine not run")
+	}
+	if g.Err().Error() != "xxx" {
+		t.Fatal("err not xxx", g.Err())
+	}
+}
+
+func testGoOptionalPanic(t *testing.T, g GoGroupExt) {
+	g.GoOptional(func(ctx context.Context) {
+		panic("not ignored")
+	}, false)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	if !strings.Contains(g.Err().Error(), "not ignored") {
+		t.Fatal("panic of optional goroutine should cancel group", g.Err())
+	}
+}
+
+func testGoOptionalOnly(t *testing.T, g GoGroupExt) {
+	g.GoOptional(func(ctx context.Context) {}, false)
+	g.GoOptional(func(ctx context.Context) {
+		time.Sleep(time.Millisecond * 10)
+	}, false)
+	g.Wait()
+	if g.Err() == nil {
+		t.Fatal("group should be canceled after the last goroutine exit")
+	}
+}
+
+func testGoOptionalBeforeGo(t *testing.T, g GoGroupExt) {
+	g.GoOptional(func(ctx context.Context) {}, false)
+	time.Sleep(time.Millisecond * 20)
+	var canceled atomic.Bool
+	g.Go(func(ctx context.Context) {
+		canceled.Store(isContextDone(ctx))
+	})
+	g.Wait()
+	if canceled.Load() {
+		t.Fatal("optional goroutine exit should not cancel group before f5", g.Err())
+	}
+	var ge *GoExitError
+	if !errors.As(g.Err(), &ge) || !strings.Contains(ge.FuncInfo.FuncName, "testGoOptionalBeforeGo") {
+		t.Fatal("group should be canceled by the required goroutine", g.Err())
+	}
+}
+
+func testGoOptionalWaitIdle(t *testing.T, g GoGroupExt) {
+	g.GoOptional(func(ctx context.Context) {}, false)
+	time.Sleep(time.Millisecond * 20)
+	g.Wait()
+	if !errors.Is(g.Err(), ErrAllExited) {
+		t.Fatal("err not ErrAllExited", g.Err())
+	}
+}
+
+var errSentinel = errors.New("sentinel")
+
+type testErr struct{ code int }
+
+func (e *testErr) Error() string { return fmt.Sprintf("test err %d", e.code) }
+
+func testGoErr(t *testing.T, g GoGroupExt) {
+	g.GoErr(func(ctx context.Context) error {
+		time.Sleep(time.Millisecond * 10)
+		return fmt.Errorf("wrap: %w", errSentinel)
+	})
+	g.GoErr(func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	err := g.Err()
+	if !errors.Is(err, errSentinel) {
+		t.Fatal("errors.Is not reach sentinel", err)
+	}
+}
+
+func testGoTkErr(t *testing.T, g GoGroupExt) {
+	var n atomic.Int32
+	g.GoTkErr(func() error {
+		if n.Add(1) == 3 {
+			return &testErr{code: 3}
+		}
+		return nil
+	}, time.Millisecond)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	var te *testErr
+	if !errors.As(g.Err(), &te) || te.code != 3 {
+		t.Fatal("errors.As not reach testErr", g.Err())
+	}
+}
+
+func testGoErrNil(t *testing.T, g GoGroupExt) {
+	g.GoErr(func(ctx context.Context) error {
+		return nil
+	})
+	if !strings.HasSuffix(g.Err().Error(), ": exit") {
+		t.Fatal("nil should be a normal exit", g.Err())
+	}
+}
+
+func testTypedErrors(t *testing.T, g GoGroup) {
+	g.Go(func(ctx context.Context) {
+		panic("typed")
+	})
+	var pe *PanicError
+	if !errors.As(g.Err(), &pe) {
+		t.Fatal("err not PanicError", g.Err())
+	}
+	if pe.Value != "typed" || pe.GoroutineID == 0 || len(pe.Stack) == 0 {
+		t.Fatal("PanicError not right", pe.Value, pe.GoroutineID)
+	}
+	if !strings.HasPrefix(string(pe.Stack), "goroutine ") {
+		t.Fatal("stack not start with goroutine", string(pe.Stack))
+	}
+	if !strings.Contains(pe.FuncInfo.FuncName, "testTypedErrors") {
+		t.Fatal("FuncInfo not right", pe.FuncInfo)
+	}
+}
+
+func testTypedExitError(t *testing.T, g GoGroup) {
+	g.Go(func(ctx context.Context) {})
+	var ge *GoExitError
+	if !errors.As(g.Err(), &ge) {
+		t.Fatal("err not GoExitError", g.Err())
+	}
+	if ge.Err != nil || !strings.Contains(ge.FuncInfo.FuncName, "testTypedExitError") {
+		t.Fatal("GoExitError not right", ge)
+	}
+}
+
+func testLimit(t *testing.T, g GoGroupExt) {
+	g.SetLimit(2)
+	var cur, max atomic.Int32
+	block := make(chan struct{})
+	work := func(ctx context.Context) {
+		n := cur.Add(1)
+		for {
+			m := max.Load()
+			if n <= m || max.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		<-block
+		cur.Add(-1)
+	}
+	g.GoOptional(work, false)
+	g.GoOptional(work, false)
+	if g.TryGo(work) {
+		t.Fatal("TryGo should fail when limit reached")
+	}
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		close(block)
+	}()
+	for i := 0; i < 4; i++ {
+		g.GoOptional(work, false) // block until a slot free
+	}
+	g.Wait()
+	if max.Load() != 2 {
+		t.Fatalf("max running %d, not 2", max.Load())
+	}
+}
+
+func testLimitFanOut(t *testing.T, g GoGroupExt) {
+	g.SetLimit(1)
+	var canceled atomic.Int32
+	for i := 0; i < 5; i++ {
+		g.GoOptional(func(ctx context.Context) {
+			if isContextDone(ctx) {
+				canceled.Add(1)
+			}
+			time.Sleep(time.Millisecond)
+		}, false)
+	}
+	g.Wait()
+	if canceled.Load() != 0 {
+		t.Fatalf("%d items run on a canceled ctx", canceled.Load())
+	}
+}
+
+func testSetLimitRace(t *testing.T, g GoGroupExt) {
+	go func() {
+		defer func() {
+			recover() // goroutines may be running already
+		}()
+		g.SetLimit(2)
+	}()
+	for i := 0; i < 10; i++ {
+		g.TryGo(func(ctx context.Context) { <-ctx.Done() })
+	}
+	g.CancelAndWait(nil)
+}
+
+func testTryGo(t *testing.T, g GoGroupExt) {
+	g.SetLimit(1)
+	if !g.TryGo(func(ctx context.Context) { <-ctx.Done() }) {
+		t.Fatal("TryGo should succeed")
+	}
+	if g.TryGo(func(ctx context.Context) {}) {
+		t.Fatal("TryGo should fail")
+	}
+	var p any
+	func() {
+		defer func() {
+			p = recover()
+		}()
+		g.SetLimit(3)
+	}()
+	if p == nil {
+		t.Fatal("SetLimit should panic when goroutines running")
+	}
+	p = nil
+	func() {
+		defer func() {
+			p = recover()
+		}()
+		g.SetLimit(-1)
+	}()
+	if p == nil {
+		t.Fatal("SetLimit(-1) should panic when goroutines running")
+	}
+	g.CancelAndWait(nil)
+}
@@ -0,0 +1,81 @@
+package gogroup
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// Handler returns a http.Handler that shows state of all registered groups and History, see Register.
+// it renders HTML, or JSON with ?format=json. e.g.
+//
+//	http.Handle("/debug/gogroup", gogroup.Handler())
+func Handler() http.Handler {
+	return http.HandlerFunc(serveDebug)
+}
+
+type debugGroup struct {
+	Name     string    `json:"name"`
+	Snapshot *Snapshot `json:"snapshot"`
+}
+
+func serveDebug(w http.ResponseWriter, r *http.Request) {
+	groups := make([]debugGroup, 0)
+	for _, g := range append(Groups(), History()...) {
+		groups = append(groups, debugGroup{Name: g.Name(), Snapshot: g.Snapshot()})
+	}
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(groups); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := debugTemplate.Execute(w, groups); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var debugTemplate = template.Must(template.New("gogroup").Funcs(template.FuncMap{
+	"time": func(t any) string {
+		switch t := t.(type) {
+		case time.Time:
+			return t.Format(microsecondDate)
+		case *time.Time:
+			return t.Format(microsecondDate)
+		}
+		return ""
+	},
+}).Parse(`<html>
+<head>
+<title>/debug/gogroup</title>
+<style>
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #ccc; padding: 2px 6px; text-align: left; }
+</style>
+</head>
+<body>
+<p>{{len .}} groups. <a href="?format=json">json</a></p>
+{{range .}}{{$s := .Snapshot}}
+<h2>{{if .Name}}{{.Name}}{{else}}(unnamed){{end}}: {{$s.State}}</h2>
+<p>
+{{if $s.FirstUseLine}}FirstUse: {{time $s.FirstUseTime}} at {{$s.FirstUseLine}}<br>{{end}}
+{{if $s.CancelTime}}{{$s.Canceler}} at {{time $s.CancelTime}}<br>{{end}}
+{{if $s.Cause}}Cause: {{$s.Cause}}<br>{{end}}
+</p>
+<table>
+<tr><th>Running</th><th>Goroutine</th><th>Uptime</th><th>Attempt</th><th>Interval</th></tr>
+{{range $s.Running}}<tr><td>{{.FuncInfo}}</td><td>{{.GoroutineID}}</td><td>{{.Uptime}}</td><td>{{.Attempt}}</td><td>{{if .Interval}}{{.Interval}}{{end}}</td></tr>
+{{end}}</table>
+<table>
+<tr><th>Exited</th><th>ExitTime</th><th>Duration</th><th>Interval</th><th>Error</th><th>Panic</th></tr>
+{{range $s.Exited}}<tr><td>{{.FuncInfo}}</td><td>{{time .ExitTime}}</td><td>{{.ExitTime.Sub .StartTime}}</td><td>{{if .Interval}}{{.Interval}}{{end}}</td><td>{{if .Err}}{{.Err}}{{end}}</td><td>{{if .Panic}}{{.Panic}}{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,90 @@
+package gogroup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	var g Group
+	g.SetName("debug-group")
+	Register(&g)
+	defer Unregister(&g)
+	mg := NewMini(context.Background())
+	mg.SetName("debug-mini")
+	Register(mg)
+	defer Unregister(mg)
+
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.GoTk(func() {}, time.Millisecond*20)
+	g.GoOptional(func(ctx context.Context) {}, false)
+	mg.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	mg.Cancel(errors.New("mini stop"))
+	time.Sleep(time.Millisecond * 50)
+
+	srv := httptest.NewServer(Handler())
+	defer srv.Close()
+
+	body := get(t, srv.URL+"?format=json")
+	var groups []struct {
+		Name     string `json:"name"`
+		Snapshot struct {
+			State    string `json:"state"`
+			Canceler string `json:"canceler"`
+			Cause    string `json:"cause"`
+			Running  []struct {
+				Interval string `json:"interval"`
+			} `json:"running"`
+			Exited []GoInfo `json:"exited"`
+		} `json:"snapshot"`
+	}
+	if err := json.Unmarshal([]byte(body), &groups); err != nil {
+		t.Fatal(err, body)
+	}
+	if len(groups) != 2 || groups[0].Name != "debug-group" || groups[1].Name != "debug-mini" {
+		t.Fatal("groups not right", body)
+	}
+	if s := groups[0].Snapshot; s.State != "Running" || len(s.Running) != 2 || len(s.Exited) != 1 ||
+		s.Running[1].Interval != "20ms" {
+		t.Fatal("group not right", body)
+	}
+	if s := groups[1].Snapshot; s.Canceler != "CancelByUser" || s.Cause != "mini stop" {
+		t.Fatal("mini group not right", body)
+	}
+
+	body = get(t, srv.URL)
+	for _, s := range []string{"debug-group: Running", "debug-mini", "CancelByUser", "mini stop", "20ms", "TestHandler.func3"} {
+		if !strings.Contains(body, s) {
+			t.Fatal("html not contain", s, "\n", body)
+		}
+	}
+	g.CancelAndWait(nil)
+	mg.Wait()
+	if !strings.Contains(get(t, srv.URL), "debug-group: Exited") {
+		t.Fatal("group not exited")
+	}
+}
+
+func get(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(bs)
+}
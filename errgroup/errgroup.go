@@ -0,0 +1,144 @@
+// Package errgroup has the same API as golang.org/x/sync/errgroup, backed by gogroup.Group.
+// code migrates by changing the import path from golang.org/x/sync/errgroup to github.com/xiaotushaoxia/gogroup/errgroup.
+// unlike x/sync/errgroup, a panic is recovered and becomes the error of Wait (a *gogroup.PanicError),
+// and ExitInfo tells which goroutine failed and when.
+package errgroup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/xiaotushaoxia/gogroup"
+)
+
+// Group is a collection of goroutines working on subtasks that are part of the same overall task.
+// zero value is ready to use, and it can be reused after Wait returns
+type Group struct {
+	cancel func(error)
+
+	m        sync.Mutex
+	g        *gogroup.Group // goroutines started since the last Wait, nil if none
+	exitInfo *gogroup.ExitInfo
+
+	sem chan struct{}
+
+	errOnce sync.Once
+	err     error
+}
+
+// WithContext returns a new Group and an associated Context derived from ctx.
+// the derived Context is canceled the first time a function passed to Go returns a non-nil error
+// or panics, or the first time Wait returns, whichever occurs first
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls f in a new goroutine.
+// it blocks until the new goroutine can be added without the number of active goroutines exceeding the limit.
+// the first call to return a non-nil error or to panic cancels the group's context, if the group was created by WithContext.
+// the error will be returned by Wait
+func (eg *Group) Go(f func() error) {
+	if eg.sem != nil {
+		eg.sem <- struct{}{}
+	}
+	eg.start(f)
+}
+
+// TryGo calls f in a new goroutine only if the number of active goroutines is below the limit. see SetLimit
+func (eg *Group) TryGo(f func() error) bool {
+	if eg.sem != nil {
+		select {
+		case eg.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	eg.start(f)
+	return true
+}
+
+// SetLimit limits the number of active goroutines to at most n. a negative n means no limit.
+// it must not be modified while any goroutines in the group are active
+func (eg *Group) SetLimit(n int) {
+	if n < 0 {
+		eg.sem = nil
+		return
+	}
+	if len(eg.sem) != 0 {
+		panic(fmt.Errorf("errgroup: modify limit while %v goroutines in the group are still active", len(eg.sem)))
+	}
+	eg.sem = make(chan struct{}, n)
+}
+
+// Wait blocks until all goroutines started by Go have returned,
+// then returns the first non-nil error (or *gogroup.PanicError) from them.
+// an error is kept after Wait returns, so later Wait returns it too, as x/sync/errgroup does
+func (eg *Group) Wait() error {
+	eg.m.Lock()
+	g := eg.g
+	eg.m.Unlock()
+	if g != nil {
+		g.Wait()
+		eg.m.Lock()
+		eg.g = nil // Go after Wait starts a new gogroup.Group
+		eg.exitInfo = g.ExitInfo()
+		eg.m.Unlock()
+	}
+	if eg.cancel != nil {
+		eg.cancel(eg.err)
+	}
+	return eg.err
+}
+
+// ExitInfo returns ExitInfo of goroutines waited by the last Wait, nil if Wait didn't wait any
+func (eg *Group) ExitInfo() *gogroup.ExitInfo {
+	eg.m.Lock()
+	defer eg.m.Unlock()
+	return eg.exitInfo
+}
+
+func (eg *Group) start(f func() error) {
+	eg.m.Lock()
+	if eg.g == nil {
+		eg.g = gogroup.New(context.Background())
+		eg.g.AddHooks(errHooks{eg: eg})
+	}
+	g := eg.g
+	eg.m.Unlock()
+	g.GoOptionalErrWithFuncInfo(func(context.Context) error {
+		defer eg.done()
+		return f()
+	}, gogroup.ParserFuncInfo(f))
+}
+
+func (eg *Group) done() {
+	if eg.sem != nil {
+		<-eg.sem
+	}
+}
+
+func (eg *Group) setErr(err error) {
+	eg.errOnce.Do(func() {
+		eg.err = err
+		if eg.cancel != nil {
+			eg.cancel(eg.err)
+		}
+	})
+}
+
+// errHooks records the first error or panic of goroutines
+type errHooks struct {
+	gogroup.NopHooks
+	eg *Group
+}
+
+func (h errHooks) OnGoExit(gi gogroup.GoInfo) {
+	switch {
+	case gi.Panic != nil:
+		h.eg.setErr(&gogroup.PanicError{FuncInfo: gi.FuncInfo, Value: gi.Panic, Stack: gi.PanicStack, Frames: gi.PanicFrames})
+	case gi.Err != nil:
+		h.eg.setErr(gi.Err)
+	}
+}
@@ -0,0 +1,204 @@
+package errgroup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/xiaotushaoxia/gogroup"
+)
+
+var errSentinel = errors.New("sentinel")
+
+func TestGroup(t *testing.T) {
+	var eg Group
+	var n atomic.Int32
+	for i := 0; i < 10; i++ {
+		eg.Go(func() error {
+			n.Add(1)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		t.Fatal("err not nil", err)
+	}
+	if n.Load() != 10 {
+		t.Fatal("not all goroutines run")
+	}
+}
+
+func TestWithContext(t *testing.T) {
+	eg, ctx := WithContext(context.Background())
+	eg.Go(func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	eg.Go(func() error {
+		time.Sleep(time.Millisecond * 10)
+		return errSentinel
+	})
+	eg.Go(func() error {
+		return nil
+	})
+	if err := eg.Wait(); err != errSentinel {
+		t.Fatal("err not sentinel", err)
+	}
+	if context.Cause(ctx) != errSentinel {
+		t.Fatal("ctx should be canceled by the first error", context.Cause(ctx))
+	}
+	if len(eg.ExitInfo().GoInfos) != 3 {
+		t.Fatal("GoInfos not 3")
+	}
+}
+
+func TestSequentialGo(t *testing.T) {
+	eg, ctx := WithContext(context.Background())
+	eg.Go(func() error {
+		return nil
+	})
+	time.Sleep(time.Millisecond * 20)
+	eg.Go(func() error {
+		if ctx.Err() != nil {
+			return errSentinel
+		}
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		t.Fatal("ctx canceled before the second Go", err)
+	}
+}
+
+func TestWaitCancel(t *testing.T) {
+	eg, ctx := WithContext(context.Background())
+	if err := eg.Wait(); err != nil {
+		t.Fatal("err not nil", err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("ctx should be canceled when Wait returns")
+	}
+}
+
+func TestParentCanceled(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	eg, ctx := WithContext(parent)
+	eg.Go(func() error {
+		<-ctx.Done()
+		return nil
+	})
+	cancel()
+	if err := eg.Wait(); err != nil {
+		t.Fatal("err should be nil when all funcs return nil", err)
+	}
+}
+
+func TestErrorAfterParentCanceled(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	eg, ctx := WithContext(parent)
+	eg.Go(func() error {
+		<-ctx.Done()
+		return errSentinel
+	})
+	cancel()
+	if err := eg.Wait(); err != errSentinel {
+		t.Fatal("err returned after parent canceled is lost", err)
+	}
+}
+
+func TestFirstError(t *testing.T) {
+	var eg Group
+	second := errors.New("second")
+	eg.Go(func() error {
+		return errSentinel
+	})
+	eg.Go(func() error {
+		time.Sleep(time.Millisecond * 20)
+		return second
+	})
+	if err := eg.Wait(); err != errSentinel {
+		t.Fatal("err not the first one", err)
+	}
+}
+
+func TestReuse(t *testing.T) {
+	var eg Group
+	eg.Go(func() error {
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		t.Fatal("err not nil", err)
+	}
+	var n atomic.Int32
+	eg.Go(func() error {
+		n.Add(1)
+		return errSentinel
+	})
+	if err := eg.Wait(); err != errSentinel {
+		t.Fatal("err not sentinel", err)
+	}
+	if n.Load() != 1 {
+		t.Fatal("goroutine after Wait not run")
+	}
+	if len(eg.ExitInfo().GoInfos) != 1 {
+		t.Fatal("ExitInfo should only have goroutines of the last Wait")
+	}
+}
+
+func TestGoInGoroutine(t *testing.T) {
+	var eg Group
+	var n atomic.Int32
+	eg.Go(func() error {
+		time.Sleep(time.Millisecond * 10)
+		eg.Go(func() error {
+			n.Add(1)
+			return nil
+		})
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		t.Fatal("err not nil", err)
+	}
+	if n.Load() != 1 {
+		t.Fatal("nested Go not waited")
+	}
+}
+
+func TestPanic(t *testing.T) {
+	eg, ctx := WithContext(context.Background())
+	eg.Go(func() error {
+		panic("boom")
+	})
+	var pe *gogroup.PanicError
+	if err := eg.Wait(); !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Fatal("err not PanicError", err)
+	}
+	if !errors.As(context.Cause(ctx), &pe) {
+		t.Fatal("ctx should be canceled by the panic", context.Cause(ctx))
+	}
+}
+
+func TestLimit(t *testing.T) {
+	var eg Group
+	eg.SetLimit(1)
+	block := make(chan struct{})
+	if !eg.TryGo(func() error {
+		<-block
+		return nil
+	}) {
+		t.Fatal("TryGo should succeed")
+	}
+	if eg.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo should fail")
+	}
+	close(block)
+	if err := eg.Wait(); err != nil {
+		t.Fatal("err not nil", err)
+	}
+	if !eg.TryGo(func() error { return nil }) {
+		t.Fatal("TryGo should succeed after Wait")
+	}
+	if err := eg.Wait(); err != nil {
+		t.Fatal("err not nil", err)
+	}
+}
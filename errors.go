@@ -0,0 +1,53 @@
+package gogroup
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrAllExited is the cause of group when f5 is called after all goroutines exited without canceling it,
+// such as a group of only GoOptional goroutines
+var ErrAllExited = errors.New("gogroup: all goroutines exited")
+
+// GoExitError is the cause of group when one of its goroutines returns
+// use errors.As to get it from Err()
+type GoExitError struct {
+	FuncInfo FuncInfo
+	Err      error // error returned by the goroutine (see GoErr), nil for a normal exit
+}
+
+func (e *GoExitError) Error() string {
+	if e.Err == nil {
+		return e.FuncInfo.String() + ": exit"
+	}
+	return e.FuncInfo.String() + ": " + e.Err.Error()
+}
+
+func (e *GoExitError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is the cause of group when one of its goroutines panics
+// use errors.As to get it from Err()
+type PanicError struct {
+	FuncInfo    FuncInfo
+	Value       any     // the value passed to panic
+	Stack       []byte  // text form of Frames
+	Frames      []Frame // stack of the panicked goroutine, without frames of gogroup and runtime
+	GoroutineID int64   // 0 if unknown
+}
+
+func (e *PanicError) Error() string {
+	if e.GoroutineID == 0 {
+		return e.FuncInfo.String() + fmt.Sprintf(": panic(%v) exit", e.Value)
+	}
+	return e.FuncInfo.String() + ": goroutine " + strconv.FormatInt(e.GoroutineID, 10) +
+		fmt.Sprintf(": panic(%v) exit", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
@@ -0,0 +1,42 @@
+package gogroup
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGoExitError(t *testing.T) {
+	fi := FuncInfo{FuncName: "f", File: "a.go", Line: 1}
+	err := &GoExitError{FuncInfo: fi}
+	if err.Error() != "func f in file a.go:1: exit" {
+		t.Fatal("Error not right", err.Error())
+	}
+	if err.Unwrap() != nil {
+		t.Fatal("Unwrap not nil")
+	}
+	err = &GoExitError{FuncInfo: fi, Err: errSentinel}
+	if err.Error() != "func f in file a.go:1: sentinel" {
+		t.Fatal("Error not right", err.Error())
+	}
+	if !errors.Is(err, errSentinel) {
+		t.Fatal("errors.Is not reach sentinel")
+	}
+}
+
+func TestPanicError(t *testing.T) {
+	fi := FuncInfo{FuncName: "f", File: "a.go", Line: 1}
+	err := &PanicError{FuncInfo: fi, Value: 1, GoroutineID: 9}
+	if err.Error() != "func f in file a.go:1: goroutine 9: panic(1) exit" {
+		t.Fatal("Error not right", err.Error())
+	}
+	if err.Unwrap() != nil {
+		t.Fatal("Unwrap not nil")
+	}
+	err = &PanicError{FuncInfo: fi, Value: errSentinel}
+	if err.Error() != "func f in file a.go:1: panic(sentinel) exit" {
+		t.Fatal("Error not right", err.Error())
+	}
+	if !errors.Is(err, errSentinel) {
+		t.Fatal("errors.Is not reach sentinel")
+	}
+}
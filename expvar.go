@@ -0,0 +1,87 @@
+package gogroup
+
+import (
+	"context"
+	"expvar"
+	"strconv"
+)
+
+// Stats is counters and state of a group, it is cheap to get. see PublishExpvar
+type Stats struct {
+	State        GroupState `json:"state"`
+	Running      int64      `json:"running"` // goroutines not exited
+	Exits        int64      `json:"exits"`   // exits of goroutines, including panics and restarts
+	Panics       int64      `json:"panics"`
+	LastCause    string     `json:"last_cause,omitempty"` // cause of group, empty if not canceled
+	FirstUseLine string     `json:"first_use_line,omitempty"`
+}
+
+func (g *groupBase) countExit(ei GoInfo) {
+	g.exitCount.Add(1)
+	if ei.Panic != nil {
+		g.panicCount.Add(1)
+	}
+}
+
+func (g *groupBase) stats() Stats {
+	s := Stats{Exits: g.exitCount.Load(), Panics: g.panicCount.Load()}
+	if isContextDone(g.ctx) {
+		s.LastCause = context.Cause(g.ctx).Error()
+	}
+	return s
+}
+
+// Stats returns counters and state of Group without blocking
+func (g *Group) Stats() Stats {
+	g.init()
+	s := g.stats()
+	s.State = GroupState(g.state.Load())
+	s.Running = int64(g.running.Load())
+	s.FirstUseLine = g.firstUseLine
+	return s
+}
+
+// Stats returns counters and state of MiniGroup without blocking. FirstUseLine is always empty
+func (g *MiniGroup) Stats() Stats {
+	g.init()
+	s := g.stats()
+	s.Running = int64(g.running.Load())
+	switch {
+	case g.exited.Load():
+		s.State = GroupStateExited
+	case g.started.Load():
+		s.State = GroupStateRunning
+	}
+	return s
+}
+
+// PublishExpvar publishes Stats of all registered groups and History as expvar name, see Register.
+// groups without a Stats method, such as other implementations of Inspectable, are skipped.
+// the value is a map keyed by group name, it is updated live. like expvar.Publish, it panics if name is used
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return expvarStats(append(Groups(), History()...))
+	}))
+}
+
+// expvarStats keys stats by name of groups, unnamed groups are "group", duplicate names get suffix "#2", "#3"...
+func expvarStats(groups []Inspectable) map[string]Stats {
+	m := make(map[string]Stats, len(groups))
+	seen := make(map[string]int, len(groups))
+	for _, g := range groups {
+		sg, ok := g.(interface{ Stats() Stats })
+		if !ok {
+			continue
+		}
+		name := g.Name()
+		if name == "" {
+			name = "group"
+		}
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name += "#" + strconv.Itoa(n)
+		}
+		m[name] = sg.Stats()
+	}
+	return m
+}
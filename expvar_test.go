@@ -0,0 +1,98 @@
+package gogroup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"sync"
+	"testing"
+)
+
+func testStats(t *testing.T, g interface {
+	GoGroupExt
+	Stats() Stats
+}) Stats {
+	g.GoOptional(func(ctx context.Context) {
+		panic("stats")
+	}, true)
+	g.GoOptional(func(ctx context.Context) {}, false)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	if s := g.Stats(); s.State != GroupStateRunning || s.LastCause != "" {
+		t.Fatal("Stats not right", s)
+	}
+	g.Cancel(errors.New("stats stop"))
+	g.Wait()
+	s := g.Stats()
+	if s.State != GroupStateExited || s.Running != 0 || s.Exits != 3 || s.Panics != 1 || s.LastCause != "stats stop" {
+		t.Fatal("Stats not right", s)
+	}
+	return s
+}
+
+func TestGroupStats(t *testing.T) {
+	var g Group
+	if s := testStats(t, &g); s.FirstUseLine == "" {
+		t.Fatal("FirstUseLine is empty")
+	}
+}
+
+func TestMiniGroupStats(t *testing.T) {
+	var g MiniGroup
+	testStats(t, &g)
+}
+
+// publishExpvarOnce publishes "gogroup_test" once per process, expvar.Publish panics on reuse of a name with -count > 1
+var publishExpvarOnce sync.Once
+
+func TestPublishExpvar(t *testing.T) {
+	g1 := New(context.Background(), WithName("expvar"))
+	defer Unregister(g1)
+	g2 := NewMini(context.Background(), WithName("expvar-mini"))
+	defer Unregister(g2)
+	g1.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	publishExpvarOnce.Do(func() {
+		PublishExpvar("gogroup_test")
+	})
+	get := func() map[string]map[string]any {
+		var m map[string]map[string]any
+		if err := json.Unmarshal([]byte(expvar.Get("gogroup_test").String()), &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	m := get()
+	if m["expvar"]["state"] != "Running" || m["expvar"]["running"] != float64(1) || m["expvar-mini"]["state"] != "Init" {
+		t.Fatal("expvar not right", m)
+	}
+	g1.CancelAndWait(nil)
+	m = get()
+	if m["expvar"]["state"] != "Exited" || m["expvar"]["exits"] != float64(1) || m["expvar"]["last_cause"] != "context canceled" {
+		t.Fatal("expvar not updated", m)
+	}
+}
+
+// noStatsGroup is an Inspectable without Stats
+type noStatsGroup struct{}
+
+func (noStatsGroup) Name() string        { return "dup" }
+func (noStatsGroup) Snapshot() *Snapshot { return &Snapshot{} }
+
+func Test_expvarStats(t *testing.T) {
+	var g1, g2, g3 Group
+	g1.SetName("dup")
+	g2.SetName("dup")
+	m := expvarStats([]Inspectable{&g1, &g2, noStatsGroup{}, &g3})
+	if len(m) != 3 {
+		t.Fatal("expvarStats not right", m)
+	}
+	for _, name := range []string{"dup", "dup#2", "group"} {
+		if _, ok := m[name]; !ok {
+			t.Fatal("expvarStats not contain", name, m)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package gogroup
+
+import (
+	"bytes"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Frame is a frame of goroutine stack
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+	PC       uintptr // 0 if parsed from text stack
+}
+
+// parseStack parses text stack, such as debug.Stack() or GoInfo.PanicStack, to frames
+// "created by" is parsed as the last frame
+func parseStack(bs []byte) []Frame {
+	var frames []Frame
+	lines := bytes2lines(bs)
+	for i := 0; i < len(lines); i++ {
+		fn := strings.TrimSpace(string(lines[i]))
+		if fn == "" || strings.HasPrefix(fn, "goroutine ") {
+			continue
+		}
+		if i+1 >= len(lines) {
+			break
+		}
+		loc := string(lines[i+1])
+		if !strings.HasPrefix(loc, "\t") && !strings.HasPrefix(loc, " ") {
+			continue // not a frame, such as "...additional frames elided..."
+		}
+		i++
+		fr := Frame{Function: frameFuncName(fn)}
+		loc = strings.TrimSpace(loc)
+		if j := strings.LastIndex(loc, " +0x"); j >= 0 {
+			loc = loc[:j]
+		}
+		if j := strings.LastIndexByte(loc, ':'); j >= 0 {
+			fr.File = loc[:j]
+			fr.Line, _ = strconv.Atoi(loc[j+1:])
+		} else {
+			fr.File = loc
+		}
+		frames = append(frames, fr)
+	}
+	return frames
+}
+
+// frameFuncName strips args of function line in text stack.
+// main.(*T).f(0xc000010000, ...) => main.(*T).f
+// created by main.main in goroutine 1 => main.main
+func frameFuncName(s string) string {
+	if strings.HasPrefix(s, "created by ") {
+		s = strings.TrimPrefix(s, "created by ")
+		if i := strings.Index(s, " in goroutine "); i >= 0 {
+			s = s[:i]
+		}
+		return s
+	}
+	if strings.HasSuffix(s, ")") {
+		if i := strings.LastIndexByte(s, '('); i > 0 {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// formatFrames renders frames to text like debug.Stack(), without goroutine header
+func formatFrames(frames []Frame) []byte {
+	var buf bytes.Buffer
+	for _, fr := range frames {
+		buf.WriteString(fr.Function)
+		buf.WriteString("()\n\t")
+		buf.WriteString(fr.File)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(fr.Line))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// pkgDir is the source directory of gogroup, frames in it are gogroup internal except tests
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return path.Dir(file)
+}()
+
+// panicFrames returns frames of the panicked goroutine, must be called in the deferred function who recovers.
+// frames of gogroup, runtime (gopanic, sigpanic, goexit...), pprof.Do and runtime/trace are filtered out
+func panicFrames() []Frame {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(1, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, len(pcs)*2)
+	}
+	var frames []Frame
+	it := runtime.CallersFrames(pcs)
+	for {
+		fr, more := it.Next()
+		if !isInternalFrame(fr) {
+			frames = append(frames, Frame{Function: fr.Function, File: fr.File, Line: fr.Line, PC: fr.PC})
+		}
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+func isInternalFrame(fr runtime.Frame) bool {
+	if strings.HasPrefix(fr.Function, "runtime.") || strings.HasPrefix(fr.Function, "runtime/trace.") ||
+		fr.Function == "runtime/pprof.Do" {
+		return true
+	}
+	return path.Dir(fr.File) == pkgDir && !strings.HasSuffix(fr.File, "_test.go")
+}
@@ -0,0 +1,76 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testPanicFrames(t *testing.T, g GoGroup) {
+	g.Go(func(ctx context.Context) {
+		var m map[string]int
+		panicFramesHelper(m)
+	})
+	g.Wait()
+	var pe *PanicError
+	if !errors.As(g.Err(), &pe) {
+		t.Fatal("err not PanicError", g.Err())
+	}
+	if len(pe.Frames) < 2 {
+		t.Fatal("frames not right", pe.Frames)
+	}
+	if !strings.HasSuffix(pe.Frames[0].Function, ".panicFramesHelper") || pe.Frames[0].PC == 0 {
+		t.Fatal("first frame not panicFramesHelper", pe.Frames[0])
+	}
+	if !strings.Contains(pe.Frames[1].Function, ".testPanicFrames.func1") {
+		t.Fatal("second frame not goroutine func", pe.Frames[1])
+	}
+	for _, fr := range pe.Frames {
+		if strings.HasPrefix(fr.Function, "runtime.") || !strings.HasSuffix(fr.File, "_test.go") {
+			t.Fatal("internal frame not filtered", fr)
+		}
+	}
+	if !strings.HasPrefix(string(pe.Stack), "goroutine ") || !strings.Contains(string(pe.Stack), "panicFramesHelper") {
+		t.Fatal("stack not right", string(pe.Stack))
+	}
+}
+
+func panicFramesHelper(m map[string]int) {
+	m["x"] = 1 // assignment to entry in nil map
+}
+
+func TestGroupPanicFrames(t *testing.T) {
+	var g Group
+	testPanicFrames(t, &g)
+	if len(g.ExitInfo().GoInfos[0].PanicFrames) == 0 {
+		t.Fatal("PanicFrames of GoInfo is empty")
+	}
+}
+
+func TestMiniGroupPanicFrames(t *testing.T) {
+	var g MiniGroup
+	testPanicFrames(t, &g)
+}
+
+func Test_parseStack(t *testing.T) {
+	st := []byte(`goroutine 7 [running]:
+main.(*T).f(0xc000010000, {0x1, 0x2})
+	/a/b/main.go:12 +0x1d
+main.main()
+	/a/b/main.go:20 +0x25
+created by main.start in goroutine 1
+	/a/b/start.go:5 +0xc5
+`)
+	frames := parseStack(st)
+	if len(frames) != 3 {
+		t.Fatal("frames not right", frames)
+	}
+	if frames[0] != (Frame{Function: "main.(*T).f", File: "/a/b/main.go", Line: 12}) ||
+		frames[2] != (Frame{Function: "main.start", File: "/a/b/start.go", Line: 5}) {
+		t.Fatal("frames not right", frames)
+	}
+	if len(parseStack(formatFrames(frames))) != 3 {
+		t.Fatal("formatFrames not right", string(formatFrames(frames)))
+	}
+}
@@ -2,6 +2,7 @@ package gogroup
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,9 +19,13 @@ const (
 	cancelFlagCancelBySubGoroutine int32 = 3
 )
 
-func New(ctx context.Context) *Group {
+func New(ctx context.Context, opts ...Option) *Group {
 	g := &Group{groupBase: groupBase{root: ctx}}
 	g.init()
+	if o := applyOptions(opts); o.name != "" {
+		g.SetName(o.name)
+		Register(g)
+	}
 	return g
 }
 
@@ -38,6 +43,28 @@ type Group struct {
 	cancelFlag atomic.Int32 // init:0,cancel by user 1;cancel by sub goroutine:2; cancel by root ctx: 3
 
 	watchRootOnce sync.Once
+	running       atomic.Int32 // goroutines started by Go functions and not exited
+	traceOn       atomic.Bool
+	trace         atomic.Pointer[groupTrace] // nil if trace is not enabled or g is not used
+
+	livesM sync.Mutex
+	lives  map[*liveGo]struct{}
+
+	readyM         sync.Mutex
+	readies        map[string]*readyState
+	startupTimeout atomic.Int64 // time.Duration
+
+	watchdog atomic.Pointer[watchdog]
+
+	parent      *Group // nil if not a child group
+	childPolicy ChildPolicy
+	childFi     FuncInfo
+	waitingC    chan struct{} // closed when f5 is called, children enter waiting too. see superviseChild
+	children    []*ExitInfo // protected by exitsM
+
+	phasesM         sync.Mutex
+	phases          map[int]*phase
+	drivePhasesOnce sync.Once
 
 	firstUseLine string
 	firstUseTime time.Time
@@ -52,7 +79,7 @@ func (g *Group) Go(f func(context.Context)) {
 
 func (g *Group) GoTk(f func(), d time.Duration) {
 	g.init()
-	g.goWithFuncInfo(toTkFunc(f, d), ParserFuncInfo(f))
+	g.goWithSpec(toErrFunc(toTkFunc(f, d)), goSpec{fi: ParserFuncInfo(f), interval: d})
 }
 
 func (g *Group) GoWithFuncInfo(f func(context.Context), fi FuncInfo) {
@@ -61,7 +88,111 @@ func (g *Group) GoWithFuncInfo(f func(context.Context), fi FuncInfo) {
 }
 
 func (g *Group) GoTkWithFuncInfo(f func(), d time.Duration, fi FuncInfo) {
-	g.goWithFuncInfo(toTkFunc(f, d), fi)
+	g.goWithSpec(toErrFunc(toTkFunc(f, d)), goSpec{fi: fi, interval: d})
+}
+
+// GoWithRestart
+// same as Go, but f will be restarted according to p after it exits.
+// every attempt is recorded in ExitInfo().GoInfos with its Attempt
+func (g *Group) GoWithRestart(f func(context.Context), p RestartPolicy) {
+	g.init()
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), restart: p})
+}
+
+// GoTkWithRestart
+// same as GoTk, but with RestartPolicy. see GoWithRestart
+func (g *Group) GoTkWithRestart(f func(), d time.Duration, p RestartPolicy) {
+	g.init()
+	g.goWithSpec(toErrFunc(toTkFunc(f, d)), goSpec{fi: ParserFuncInfo(f), restart: p, interval: d})
+}
+
+// GoWithPhase
+// same as Go, but f is canceled in the shutdown phase. Go uses phase 0, phase must not be negative.
+// when Group is canceled, phases are canceled in ascending order,
+// each phase is canceled after all goroutines of previous phases exited.
+// ctx of f keeps values of the root ctx, but it isn't canceled until its phase comes.
+// e.g. http server in phase 0, db writer in phase 1, log shipper in phase 2.
+func (g *Group) GoWithPhase(f func(context.Context), phase int) {
+	g.init()
+	checkPhase(phase)
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), phase: phase})
+}
+
+// GoTkWithPhase
+// same as GoTk, but with shutdown phase. see GoWithPhase
+func (g *Group) GoTkWithPhase(f func(), d time.Duration, phase int) {
+	g.init()
+	checkPhase(phase)
+	g.goWithSpec(toErrFunc(toTkFunc(f, d)), goSpec{fi: ParserFuncInfo(f), phase: phase, interval: d})
+}
+
+// SetPanicPolicy sets what to do after a goroutine panics. see PanicPolicy
+func (g *Group) SetPanicPolicy(p PanicPolicy) {
+	g.setPanicPolicy(p)
+}
+
+// GoWithPanicPolicy
+// same as Go, but p overrides PanicPolicy of Group for f
+func (g *Group) GoWithPanicPolicy(f func(ctx context.Context), p PanicPolicy) {
+	g.init()
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), panicPolicy: &p})
+}
+
+// SetName sets name of Group, it is used as pprof label LabelGroup of goroutines started after it
+func (g *Group) SetName(name string) {
+	g.setName(name)
+}
+
+func (g *Group) Name() string {
+	return g.getName()
+}
+
+// AddHooks registers h to observe lifecycle of Group. see Hooks
+func (g *Group) AddHooks(h Hooks) {
+	g.addHooks(h)
+}
+
+// TryGo same as Go, but returns false without starting f if the limit is reached. see GoGroupExt.SetLimit
+func (g *Group) TryGo(f func(context.Context)) bool {
+	g.init()
+	g.panicIfExited()
+	sem, ok := g.tryAcquire()
+	if !ok {
+		return false
+	}
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), acquired: true, sem: sem})
+	return true
+}
+
+// SetLimit limits the number of running goroutines. see GoGroupExt.SetLimit
+func (g *Group) SetLimit(n int) {
+	g.setLimit(n)
+}
+
+// GoOptional start a non-critical goroutine. see GoGroupExt.GoOptional
+func (g *Group) GoOptional(f func(context.Context), ignorePanic bool) {
+	g.init()
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), optional: true, ignorePanic: ignorePanic})
+}
+
+// GoErr same as Go, but f returns an error. see GoGroupExt.GoErr
+func (g *Group) GoErr(f func(context.Context) error) {
+	g.init()
+	g.goWithSpec(f, goSpec{fi: ParserFuncInfo(f)})
+}
+
+// GoTkErr same as GoTk, but f returns an error. see GoGroupExt.GoTkErr
+func (g *Group) GoTkErr(f func() error, d time.Duration) {
+	g.init()
+	g.goWithSpec(toTkErrFunc(f, d), goSpec{fi: ParserFuncInfo(f), interval: d})
+}
+
+// GoOptionalErrWithFuncInfo
+// same as GoOptional(f, false) with custom FuncInfo, but f returns an error like GoErr:
+// non-nil error returned by f cancels Group, nil doesn't. it is how package errgroup starts goroutines
+func (g *Group) GoOptionalErrWithFuncInfo(f func(context.Context) error, fi FuncInfo) {
+	g.init()
+	g.goWithSpec(f, goSpec{fi: fi, optional: true})
 }
 
 func (g *Group) Cancel(err error) {
@@ -95,6 +226,11 @@ func (g *Group) Err() error {
 func (g *Group) ExitInfo() *ExitInfo {
 	g.init()
 	<-g.watch().Done()
+	return g.exitInfo()
+}
+
+// exitInfo builds ExitInfo without waiting g exit
+func (g *Group) exitInfo() *ExitInfo {
 	v := &ExitInfo{
 		FirstUseTime: g.firstUseTime,
 		Cause:        context.Cause(g.ctx),
@@ -110,7 +246,9 @@ func (g *Group) ExitInfo() *ExitInfo {
 	}
 	g.exitsM.Lock()
 	v.GoInfos = append(make([]GoInfo, 0, len(g.exits)), g.exits...)
+	v.Children = append([]*ExitInfo(nil), g.children...)
 	g.exitsM.Unlock()
+	v.Phases = g.phaseInfos()
 	ct, ok := g.cancelAt.Load().(time.Time)
 	if !ok {
 		return v
@@ -120,7 +258,7 @@ func (g *Group) ExitInfo() *ExitInfo {
 		return v
 	}
 	et, ok := g.exitTime.Load().(time.Time)
-	if !ok {
+	if !ok || et.IsZero() { // zero after reopen
 		return v
 	}
 	v.CancelTime, v.FirstGoTime, v.ExitTime = &ct, &ft, &et
@@ -131,6 +269,7 @@ func (g *Group) init() {
 	g.initOnce.Do(func() {
 		g.initBase()
 		g.waitFunc = g.waitAndSetExit
+		g.waitingC = make(chan struct{})
 		g.firstUseTime = time.Now()
 		g.firstUseLine = getCallerLine(7)
 	})
@@ -143,24 +282,160 @@ func (g *Group) panicIfExited() {
 }
 
 func (g *Group) goWithFuncInfo(f func(context.Context), fi FuncInfo) {
+	g.goWithSpec(toErrFunc(f), goSpec{fi: fi})
+}
+
+func (g *Group) goWithSpec(f func(context.Context) error, spec goSpec) {
 	g.panicIfExited()
+	queueWait := g.acquire(&spec)
 	g.watchRootContext()
-	g.state.CompareAndSwap(groupStateInit, groupStateRunning)
+	first := g.state.CompareAndSwap(groupStateInit, groupStateRunning)
+	if first {
+		g.logFirstUse()
+		g.startTrace()
+		if g.parent != nil {
+			g.parent.superviseChild(g)
+		}
+	}
 	now := time.Now()
 	if g.firstGoTime.Load() == nil {
 		g.firstGoTime.CompareAndSwap(nil, now)
 	}
+	if spec.phase != 0 {
+		g.drivePhases()
+	}
+	ph := g.enterPhase(spec.phase)
+	g.addRunning(&g.running, g.reopen)
+	if first {
+		context.AfterFunc(g.ctx, g.exitIfDone)
+	}
 	g.wg.Add(1)
+	g.logGoStart(spec.fi)
+	g.callOnGoStart(spec.fi)
+	f = g.traceGo(spec.fi, f)
 	go func() {
-		defer g.handleExit(fi, now)
-		f(g.ctx)
+		defer g.wg.Done()
+		defer g.release(spec)
+		defer g.leavePhase(ph)
+		lg := g.addLive(spec, now)
+		defer g.removeLive(lg)
+		r := restarter{policy: spec.restart}
+		ctx := g.tickContext(ph.ctx, spec)
+		for attempt, start := 1, now; ; attempt++ {
+			g.setLiveAttempt(lg, attempt, start)
+			ei, err := g.run(ctx, f, spec.fi)
+			ei.StartTime, ei.Attempt, ei.Optional, ei.Interval = start, attempt, spec.optional, spec.interval
+			if attempt == 1 {
+				ei.QueueWait = queueWait
+			}
+			wait, restart := r.next(ei)
+			if !restart || isContextDone(g.ctx) {
+				g.handleExit(ei, err, spec)
+				if ei.Panic != nil {
+					g.applyPanicPolicy(spec, ei, &g.running)
+				}
+				return
+			}
+			g.addExit(ei)
+			if !sleepContext(g.ctx, wait) {
+				g.running.Add(-1)
+				g.notifyExit()
+				g.exitIfDone()
+				return
+			}
+			g.callOnGoStart(spec.fi)
+			start = time.Now()
+		}
+	}()
+}
+
+func (g *Group) addLive(spec goSpec, start time.Time) *liveGo {
+	lg := &liveGo{fi: spec.fi, goroutineID: curGoroutineID(), startTime: start, interval: spec.interval, phase: spec.phase}
+	g.livesM.Lock()
+	if g.lives == nil {
+		g.lives = make(map[*liveGo]struct{})
+	}
+	g.lives[lg] = struct{}{}
+	g.livesM.Unlock()
+	return lg
+}
+
+func (g *Group) removeLive(lg *liveGo) {
+	g.livesM.Lock()
+	delete(g.lives, lg)
+	g.livesM.Unlock()
+}
+
+func (g *Group) setLiveAttempt(lg *liveGo, attempt int, start time.Time) {
+	g.livesM.Lock()
+	lg.attempt, lg.startTime = attempt, start
+	g.livesM.Unlock()
+}
+
+// liveGos returns copies of running goroutines, sorted by start time
+func (g *Group) liveGos() []liveGo {
+	g.livesM.Lock()
+	lgs := make([]liveGo, 0, len(g.lives))
+	for lg := range g.lives {
+		lgs = append(lgs, *lg)
+	}
+	g.livesM.Unlock()
+	sort.Slice(lgs, func(i, j int) bool {
+		return lgs[i].startTime.Before(lgs[j].startTime)
+	})
+	return lgs
+}
+
+// run calls f once, and returns its GoInfo and the error who will cancel Group
+func (g *Group) run(ctx context.Context, f func(context.Context) error, fi FuncInfo) (ei GoInfo, err error) {
+	var ferr error
+	defer func() {
+		ei, err = getGoExitInfo(fi, recover(), ferr)
 	}()
+	ferr = g.doWithLabels(ctx, fi, f)
+	return
 }
 
 func (g *Group) waitAndSetExit() {
+	g.enterWaiting()
+	close(g.waitingC)
 	g.wg.Wait()
 	g.state.Store(groupStateExited)
-	g.exitTime.Store(time.Now())
+	g.finishExit(false)
+	g.exiting.Wait() // finishExit without f5 may be still running
+}
+
+// enterWaiting is called when f5 is called, or when the parent of child g is waiting. see superviseChild
+func (g *Group) enterWaiting() {
+	// now nobody will start goroutines. so the group ends when no goroutine is running.
+	// see handleExit for the goroutine who exits after this
+	g.waiting.Store(true)
+	if g.running.Load() == 0 && g.state.Load() != groupStateInit {
+		g.cancelByCanceler(ErrAllExited, cancelFlagCancelBySubGoroutine)
+	}
+}
+
+// finishExit is called once g exited, by f5 or by exitIfDone (auto)
+func (g *Group) finishExit(auto bool) {
+	if !g.markExited(&g.running, auto, func() {
+		g.exitTime.Store(time.Now())
+		g.endTrace()
+		registryExited(g)
+	}) {
+		return
+	}
+	defer g.exiting.Done()
+	g.logExit()
+	if g.hasHooks() {
+		g.callOnGroupExit(g.exitInfo())
+	}
+}
+
+// reopen undoes finishExit without f5, called under exitM by Go who starts a goroutine after it
+func (g *Group) reopen() {
+	g.exitTime.Store(time.Time{})
+	g.startTrace()
+	registryReopened(g)
 }
 
 func (g *Group) watchRootContext() {
@@ -171,7 +446,9 @@ func (g *Group) watchRootContext() {
 			select {
 			case <-g.root.Done():
 			case <-g.ctx.Done():
-				if isContextDone(g.root) { // select is random, so maybe root is Done too
+				// select is random, so root may be Done too. then ctx is canceled by root,
+				// and cancel of sub goroutines is refused, so it must be recorded here
+				if !isContextDone(g.root) {
 					return
 				}
 			}
@@ -180,28 +457,72 @@ func (g *Group) watchRootContext() {
 	})
 }
 
-func (g *Group) handleExit(fi FuncInfo, start time.Time) {
-	p := recover()
-	ei, err := getGoExitInfo(fi, p)
-	ei.StartTime = start
+func (g *Group) handleExit(ei GoInfo, err error, spec goSpec) {
+	g.logGoExit(ei)
+	g.countExit(ei)
+	g.callOnGoExit(ei)
+	canceled := false
 	g.exitsM.Lock()
 	g.exits = append(g.exits, ei)
+	idle := g.running.Add(-1) == 0 // after ei is recorded, see exitIfDone
 	// cancel must be protected by exitsM. otherwise g may be canceled by other ei
-	g.cancelByCanceler(err, cancelFlagCancelBySubGoroutine)
+	if spec.cancelOnExit(ei) || idle && g.waiting.Load() {
+		canceled = g.cancel(err, cancelFlagCancelBySubGoroutine)
+	}
+	g.exitsM.Unlock()
+	g.notifyExit()
+	if canceled {
+		g.afterCancel(err, cancelFlagCancelBySubGoroutine)
+	}
+	g.exitIfDone()
+}
+
+// exitIfDone finishes exit of g without f5 once it is canceled and no goroutine is running,
+// so that registry, hooks and trace see the exit. it is called after either of them changes.
+// Go is still allowed until f5 is called, the goroutine runs on the canceled ctx and reopens g, see reopen
+func (g *Group) exitIfDone() {
+	if g.running.Load() == 0 && isContextDone(g.ctx) && g.state.Load() != groupStateInit && !g.exitDone.Load() {
+		go g.finishExit(true) // not to delay exit of the goroutine by hooks
+	}
+}
+
+// addExit records ei without cancel g. used when the goroutine will be restarted
+func (g *Group) addExit(ei GoInfo) {
+	g.logGoExit(ei)
+	g.countExit(ei)
+	g.callOnGoExit(ei)
+	g.exitsM.Lock()
+	g.exits = append(g.exits, ei)
 	g.exitsM.Unlock()
-	g.wg.Done()
 }
 
 func (g *Group) cancelByCanceler(err error, canceler int32) *Group {
+	if g.cancel(err, canceler) {
+		g.afterCancel(err, canceler)
+	}
+	return g
+}
+
+// cancel returns true if g is canceled by this call, then afterCancel must be called.
+// it is split from cancelByCanceler so that afterCancel can be called without holding exitsM
+func (g *Group) cancel(err error, canceler int32) bool {
 	// sometime sub goroutine exit very fast, make `watchRootContext` goroutine can't set cancelFlag
 	// so if root is Done, don't set cancelFlag = canceler
 	if canceler == cancelFlagCancelByRootContext || !isContextDone(g.root) {
 		if g.cancelFlag.CompareAndSwap(cancelFlagInit, canceler) {
 			g.cancelAt.CompareAndSwap(nil, time.Now())
 			g.cancelCause(err)
+			return true
 		}
 	}
-	return g
+	return false
+}
+
+func (g *Group) afterCancel(err error, canceler int32) {
+	g.logCancel(err, Canceler(canceler))
+	g.traceCancel(err, Canceler(canceler))
+	g.callOnCancel(err, Canceler(canceler))
+	g.startWatchdog(0)
 }
 
 // NewAndGo
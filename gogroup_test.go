@@ -2,6 +2,7 @@ package gogroup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -42,10 +43,119 @@ func TestGroupGoAfterWait(t *testing.T) {
 	testGoAfterWait(t, &g)
 }
 
+func TestGroupGoOptional(t *testing.T) {
+	var g Group
+	testGoOptional(t, &g)
+	var n int
+	for _, gi := range g.ExitInfo().GoInfos {
+		if gi.Optional {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Fatalf("%d optional GoInfos, not 2", n)
+	}
+}
+
+func TestGroupGoOptionalPanic(t *testing.T) {
+	var g Group
+	testGoOptionalPanic(t, &g)
+}
+
+func TestGroupGoOptionalOnly(t *testing.T) {
+	var g Group
+	testGoOptionalOnly(t, &g)
+}
+
+func TestGroupGoOptionalBeforeGo(t *testing.T) {
+	var g Group
+	testGoOptionalBeforeGo(t, &g)
+}
+
+func TestGroupGoOptionalWaitIdle(t *testing.T) {
+	var g Group
+	testGoOptionalWaitIdle(t, &g)
+}
+
+func TestGroupGoErr(t *testing.T) {
+	var g Group
+	testGoErr(t, &g)
+	for _, gi := range g.ExitInfo().GoInfos {
+		if gi.Err != nil && !errors.Is(gi.Err, errSentinel) {
+			t.Fatal("GoInfo.Err not sentinel", gi.Err)
+		}
+	}
+}
+
+func TestGroupGoTkErr(t *testing.T) {
+	var g Group
+	testGoTkErr(t, &g)
+}
+
+func TestGroupGoErrNil(t *testing.T) {
+	var g Group
+	testGoErrNil(t, &g)
+}
+
+func TestGroupTypedErrors(t *testing.T) {
+	var g Group
+	testTypedErrors(t, &g)
+}
+
+func TestGroupTypedExitError(t *testing.T) {
+	var g Group
+	testTypedExitError(t, &g)
+}
+
+func TestGroupLimit(t *testing.T) {
+	var g Group
+	testLimit(t, &g)
+	var queued int
+	for _, gi := range g.ExitInfo().GoInfos {
+		if gi.QueueWait > 0 {
+			queued++
+		}
+	}
+	if queued == 0 {
+		t.Fatal("QueueWait not recorded")
+	}
+}
+
+func TestGroupLimitFanOut(t *testing.T) {
+	var g Group
+	testLimitFanOut(t, &g)
+}
+
+func TestGroupSetLimitRace(t *testing.T) {
+	var g Group
+	testSetLimitRace(t, &g)
+}
+
+func TestGroupTryGo(t *testing.T) {
+	var g Group
+	testTryGo(t, &g)
+}
+
 func TestNewAndGo(t *testing.T) {
-	ctx, _ := context.WithTimeout(context.Background(), time.Microsecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Microsecond)
+	defer cancel()
 
 	andGo := NewAndGo(ctx, process, processOnce, processOnce, time.Second, process, processOnce, time.Second, process, processPanic)
 	andGo.Wait()
 	fmt.Println(andGo.Err())
 }
+
+func TestGroupCancelByRootContext(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		g := New(ctx)
+		g.Go(func(ctx context.Context) {
+			<-ctx.Done()
+		})
+		cancel()
+		g.Wait()
+		if !g.ExitInfo().CancelByRootContext {
+			t.Fatal("not CancelByRootContext", g.ExitInfo())
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package gogroup
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Heartbeat is given to f of GoHeartbeat, f should call Beat periodically to show it is alive
+type Heartbeat struct {
+	last atomic.Int64 // unix nano
+}
+
+func (h *Heartbeat) Beat() {
+	h.last.Store(time.Now().UnixNano())
+}
+
+// LastBeat returns the time of the last Beat
+func (h *Heartbeat) LastBeat() time.Time {
+	return time.Unix(0, h.last.Load())
+}
+
+// LivenessTimeoutError is reported when a goroutine started by GoHeartbeat stops beating
+type LivenessTimeoutError struct {
+	FuncInfo FuncInfo
+	Timeout  time.Duration
+	LastBeat time.Time
+}
+
+func (e *LivenessTimeoutError) Error() string {
+	return "gogroup: " + e.FuncInfo.String() + ": no heartbeat for " + e.Timeout.String() +
+		", last beat at " + e.LastBeat.Format(microsecondDate)
+}
+
+// GoHeartbeat
+// start a goroutine in Group who must call hb.Beat at least once every timeout, such as a consumer loop.
+// if it doesn't, it is treated as hung:
+// if onTimeout is nil, Group is canceled with a *LivenessTimeoutError,
+// otherwise onTimeout is called and Group keeps running, onTimeout is called again only after a new beat.
+// no liveness check after Group is canceled. timeout must be positive
+func (g *Group) GoHeartbeat(f func(ctx context.Context, hb *Heartbeat), timeout time.Duration, onTimeout func(*LivenessTimeoutError)) {
+	if timeout <= 0 {
+		panic("gogroup: non-positive heartbeat timeout " + timeout.String())
+	}
+	g.init()
+	fi := ParserFuncInfo(f)
+	g.goWithSpec(func(ctx context.Context) error {
+		hb := &Heartbeat{}
+		hb.Beat()
+		done := make(chan struct{})
+		defer close(done)
+		go g.checkHeartbeat(ctx, done, hb, fi, timeout, onTimeout)
+		f(ctx, hb)
+		return nil
+	}, goSpec{fi: fi})
+}
+
+func (g *Group) checkHeartbeat(ctx context.Context, done chan struct{}, hb *Heartbeat, fi FuncInfo,
+	timeout time.Duration, onTimeout func(*LivenessTimeoutError)) {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	var reported int64 // the beat who has been reported
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		last := hb.last.Load()
+		if wait := time.Until(time.Unix(0, last).Add(timeout)); wait > 0 {
+			t.Reset(wait)
+			continue
+		}
+		t.Reset(timeout)
+		if last == reported {
+			continue
+		}
+		reported = last
+		err := &LivenessTimeoutError{FuncInfo: fi, Timeout: timeout, LastBeat: time.Unix(0, last)}
+		if onTimeout == nil {
+			g.cancelByCanceler(err, cancelFlagCancelBySubGoroutine)
+			return
+		}
+		onTimeout(err)
+	}
+}
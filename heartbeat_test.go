@@ -0,0 +1,88 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func deadlockedConsumer(ctx context.Context, hb *Heartbeat) {
+	for i := 0; i < 3; i++ {
+		hb.Beat()
+		time.Sleep(time.Millisecond * 10)
+	}
+	<-ctx.Done() // hung, but still listening to ctx
+}
+
+func TestGroupGoHeartbeatCancel(t *testing.T) {
+	var g Group
+	g.GoHeartbeat(deadlockedConsumer, time.Millisecond*50, nil)
+	var le *LivenessTimeoutError
+	if !errors.As(g.Err(), &le) {
+		t.Fatal("err not LivenessTimeoutError", g.Err())
+	}
+	if le.FuncInfo.FuncName != ParserFuncInfo(deadlockedConsumer).FuncName {
+		t.Fatal("FuncInfo not deadlockedConsumer", le.FuncInfo)
+	}
+	if since := time.Since(le.LastBeat); since < time.Millisecond*50 {
+		t.Fatal("LastBeat not right", since)
+	}
+	if !g.ExitInfo().CancelBySubGoroutine {
+		t.Fatal("not CancelBySubGoroutine")
+	}
+}
+
+func TestGroupGoHeartbeatReport(t *testing.T) {
+	var g Group
+	var n atomic.Int32
+	g.GoHeartbeat(func(ctx context.Context, hb *Heartbeat) {
+		time.Sleep(time.Millisecond * 50) // miss beats, report once
+		hb.Beat()
+		time.Sleep(time.Millisecond * 50) // miss again, report again
+		<-ctx.Done()
+	}, time.Millisecond*20, func(err *LivenessTimeoutError) {
+		n.Add(1)
+	})
+	time.Sleep(time.Millisecond * 150)
+	g.CancelAndWait(fmt.Errorf("stop"))
+	if n.Load() != 2 {
+		t.Fatalf("reported %d times, not 2", n.Load())
+	}
+	if g.Err().Error() != "stop" {
+		t.Fatal("report should not cancel group", g.Err())
+	}
+}
+
+func TestGroupGoHeartbeatAlive(t *testing.T) {
+	var g Group
+	g.GoHeartbeat(func(ctx context.Context, hb *Heartbeat) {
+		tk := time.NewTicker(time.Millisecond * 5)
+		defer tk.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tk.C:
+				hb.Beat()
+			}
+		}
+	}, time.Millisecond*30, nil)
+	time.Sleep(time.Millisecond * 100)
+	g.CancelAndWait(fmt.Errorf("stop"))
+	if g.Err().Error() != "stop" {
+		t.Fatal("alive goroutine should not be canceled", g.Err())
+	}
+}
+
+func TestGroupGoHeartbeatBadTimeout(t *testing.T) {
+	var g Group
+	defer func() {
+		if recover() == nil {
+			t.Fatal("should panic")
+		}
+	}()
+	g.GoHeartbeat(deadlockedConsumer, 0, nil)
+}
@@ -0,0 +1,108 @@
+package gogroup
+
+import (
+	"context"
+	"time"
+)
+
+// Hooks observes lifecycle of a group, such as alerting and metrics. see Group.AddHooks
+// methods are called synchronously by the goroutine who causes the event, so they must be fast and must not block.
+// they must not call f5 of the group either. the exception is OnGroupExit of a group who exits without f5
+// (canceled and no goroutine running), it is called on a new goroutine.
+type Hooks interface {
+	OnGoStart(FuncInfo)                      // before a goroutine starts, and before each restart. see RestartPolicy
+	OnGoExit(GoInfo)                         // after a goroutine exits, including panic
+	OnPanic(GoInfo)                          // after a goroutine panics, before OnGoExit
+	OnCancel(cause error, canceler Canceler) // once, when the group is canceled
+	OnGroupExit(*ExitInfo)                   // when the group is canceled and all its goroutines exited. again if Go reopens it before f5
+}
+
+// TickHooks is an optional interface of Hooks, OnTick is called after each tick of GoTk with its duration
+type TickHooks interface {
+	OnTick(fi FuncInfo, d time.Duration)
+}
+
+// NopHooks implements Hooks with empty methods, embed it to implement only some of them
+type NopHooks struct{}
+
+func (NopHooks) OnGoStart(FuncInfo)                      {}
+func (NopHooks) OnGoExit(GoInfo)                         {}
+func (NopHooks) OnPanic(GoInfo)                          {}
+func (NopHooks) OnCancel(cause error, canceler Canceler) {}
+func (NopHooks) OnGroupExit(*ExitInfo)                   {}
+
+func (g *groupBase) addHooks(h Hooks) {
+	g.hooksM.Lock()
+	g.hooks = append(g.hooks[:len(g.hooks):len(g.hooks)], h)
+	g.hooksM.Unlock()
+}
+
+func (g *groupBase) getHooks() []Hooks {
+	g.hooksM.Lock()
+	defer g.hooksM.Unlock()
+	return g.hooks
+}
+
+func (g *groupBase) hasHooks() bool {
+	return len(g.getHooks()) > 0
+}
+
+func (g *groupBase) callOnGoStart(fi FuncInfo) {
+	for _, h := range g.getHooks() {
+		h.OnGoStart(fi)
+	}
+}
+
+func (g *groupBase) callOnGoExit(ei GoInfo) {
+	for _, h := range g.getHooks() {
+		if ei.Panic != nil {
+			h.OnPanic(ei)
+		}
+		h.OnGoExit(ei)
+	}
+}
+
+func (g *groupBase) callOnCancel(cause error, canceler Canceler) {
+	for _, h := range g.getHooks() {
+		h.OnCancel(cause, canceler)
+	}
+}
+
+func (g *groupBase) callOnGroupExit(ei *ExitInfo) {
+	for _, h := range g.getHooks() {
+		h.OnGroupExit(ei)
+	}
+}
+
+func (g *groupBase) callOnTick(fi FuncInfo, d time.Duration) {
+	for _, h := range g.getHooks() {
+		if th, ok := h.(TickHooks); ok {
+			th.OnTick(fi, d)
+		}
+	}
+}
+
+// tickKey is the key of func(time.Duration) in ctx of GoTk, it observes each tick
+type tickKey struct{}
+
+// tickContext returns ctx for goroutine of spec, ticks of GoTk are reported to TickHooks
+func (g *groupBase) tickContext(ctx context.Context, spec goSpec) context.Context {
+	if spec.interval <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, tickKey{}, func(d time.Duration) {
+		g.callOnTick(spec.fi, d)
+	})
+}
+
+// tick calls f once for GoTk
+func tick(ctx context.Context, f func() error) error {
+	observe, ok := ctx.Value(tickKey{}).(func(time.Duration))
+	if !ok {
+		return traceTick(ctx, f)
+	}
+	start := time.Now()
+	err := traceTick(ctx, f)
+	observe(time.Since(start))
+	return err
+}
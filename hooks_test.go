@@ -0,0 +1,115 @@
+package gogroup
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type recordHooks struct {
+	NopHooks
+	m        sync.Mutex
+	starts   int
+	exits    int
+	panics   int
+	cancels  []Canceler
+	groupEnd []*ExitInfo
+}
+
+func (h *recordHooks) OnGoStart(FuncInfo) {
+	h.m.Lock()
+	h.starts++
+	h.m.Unlock()
+}
+
+func (h *recordHooks) OnGoExit(GoInfo) {
+	h.m.Lock()
+	h.exits++
+	h.m.Unlock()
+}
+
+func (h *recordHooks) OnPanic(GoInfo) {
+	h.m.Lock()
+	h.panics++
+	h.m.Unlock()
+}
+
+func (h *recordHooks) OnCancel(cause error, canceler Canceler) {
+	h.m.Lock()
+	h.cancels = append(h.cancels, canceler)
+	h.m.Unlock()
+}
+
+func (h *recordHooks) OnGroupExit(ei *ExitInfo) {
+	h.m.Lock()
+	h.groupEnd = append(h.groupEnd, ei)
+	h.m.Unlock()
+}
+
+func testHooks(t *testing.T, g interface {
+	GoGroup
+	AddHooks(Hooks)
+}) *recordHooks {
+	h := &recordHooks{}
+	g.AddHooks(h)
+	g.AddHooks(NopHooks{})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.Go(func(ctx context.Context) {
+		panic("hooked")
+	})
+	g.Wait()
+	g.Wait()
+	h.m.Lock()
+	defer h.m.Unlock()
+	if h.starts != 2 || h.exits != 2 || h.panics != 1 {
+		t.Fatal("hooks not called right", h.starts, h.exits, h.panics)
+	}
+	if len(h.cancels) != 1 || h.cancels[0] != CancelerSubGoroutine {
+		t.Fatal("OnCancel not called right", h.cancels)
+	}
+	if len(h.groupEnd) != 1 || h.groupEnd[0].Cause == nil {
+		t.Fatal("OnGroupExit not called right", h.groupEnd)
+	}
+	return h
+}
+
+func TestGroupHooks(t *testing.T) {
+	var g Group
+	h := testHooks(t, &g)
+	if len(h.groupEnd[0].GoInfos) != 2 || h.groupEnd[0].ExitTime == nil {
+		t.Fatal("ExitInfo of OnGroupExit not right", h.groupEnd[0])
+	}
+}
+
+func TestMiniGroupHooks(t *testing.T) {
+	var g MiniGroup
+	testHooks(t, &g)
+}
+
+func TestGroupHooksUserCancel(t *testing.T) {
+	var g Group
+	h := &recordHooks{}
+	g.AddHooks(h)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.CancelAndWait(nil)
+	if len(h.cancels) != 1 || h.cancels[0] != CancelerUser {
+		t.Fatal("OnCancel not called right", h.cancels)
+	}
+}
+
+func TestGroupHooksRestart(t *testing.T) {
+	var g Group
+	h := &recordHooks{}
+	g.AddHooks(h)
+	g.GoWithRestart(func(ctx context.Context) {
+		panic("restart")
+	}, RestartPolicy{Mode: RestartOnPanic, MaxRestarts: 2})
+	g.Wait()
+	if h.starts != 3 || h.exits != 3 || h.panics != 3 {
+		t.Fatal("hooks of restart not right", h.starts, h.exits, h.panics)
+	}
+}
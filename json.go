@@ -0,0 +1,321 @@
+package gogroup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JSON form of FuncInfo, GoInfo and ExitInfo is stable, for shipping exit reports to log pipelines.
+// times are RFC3339Nano, durations are strings like "1.5s".
+// errors and panic values are kept as strings, so unmarshal is not lossless:
+// unmarshalled Cause, Err and Panic have the same messages but not the same types.
+
+type funcInfoJSON struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Desc string `json:"desc,omitempty"`
+}
+
+func (fi FuncInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(funcInfoJSON{Func: fi.FuncName, File: fi.File, Line: fi.Line, Desc: fi.Description})
+}
+
+func (fi *FuncInfo) UnmarshalJSON(bs []byte) error {
+	var v funcInfoJSON
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	*fi = FuncInfo{FuncName: v.Func, File: v.File, Line: v.Line, Description: v.Desc}
+	return nil
+}
+
+type frameJSON struct {
+	Func string  `json:"func"`
+	File string  `json:"file"`
+	Line int     `json:"line"`
+	PC   uintptr `json:"pc,omitempty"`
+}
+
+type panicJSON struct {
+	Value string      `json:"value"`
+	Type  string      `json:"type"`
+	Stack []frameJSON `json:"stack,omitempty"`
+}
+
+type goInfoJSON struct {
+	Func      FuncInfo   `json:"func"`
+	StartTime time.Time  `json:"start_time"`
+	ExitTime  time.Time  `json:"exit_time"`
+	Duration  string     `json:"duration"`
+	QueueWait string     `json:"queue_wait,omitempty"`
+	Interval  string     `json:"interval,omitempty"`
+	Attempt   int        `json:"attempt,omitempty"`
+	Optional  bool       `json:"optional,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Panic     *panicJSON `json:"panic,omitempty"`
+}
+
+func (gi GoInfo) MarshalJSON() ([]byte, error) {
+	v := goInfoJSON{
+		Func:      gi.FuncInfo,
+		StartTime: gi.StartTime,
+		ExitTime:  gi.ExitTime,
+		Duration:  gi.ExitTime.Round(0).Sub(gi.StartTime.Round(0)).String(), // wall clock, same as unmarshalled
+		Attempt:   gi.Attempt,
+		Optional:  gi.Optional,
+	}
+	if gi.QueueWait > 0 {
+		v.QueueWait = gi.QueueWait.String()
+	}
+	if gi.Interval > 0 {
+		v.Interval = gi.Interval.String()
+	}
+	if gi.Err != nil {
+		v.Error = gi.Err.Error()
+	}
+	if gi.Panic != nil {
+		v.Panic = &panicJSON{Value: fmt.Sprint(gi.Panic), Type: fmt.Sprintf("%T", gi.Panic)}
+		frames := gi.PanicFrames
+		if frames == nil {
+			frames = parseStack(gi.PanicStack)
+		}
+		for _, fr := range frames {
+			v.Panic.Stack = append(v.Panic.Stack, frameJSON{Func: fr.Function, File: fr.File, Line: fr.Line, PC: fr.PC})
+		}
+	}
+	return json.Marshal(v)
+}
+
+func (gi *GoInfo) UnmarshalJSON(bs []byte) error {
+	var v goInfoJSON
+	err := json.Unmarshal(bs, &v)
+	if err != nil {
+		return err
+	}
+	*gi = GoInfo{
+		FuncInfo:  v.Func,
+		StartTime: v.StartTime,
+		ExitTime:  v.ExitTime,
+		Attempt:   v.Attempt,
+		Optional:  v.Optional,
+	}
+	if v.QueueWait != "" {
+		if gi.QueueWait, err = time.ParseDuration(v.QueueWait); err != nil {
+			return err
+		}
+	}
+	if v.Interval != "" {
+		if gi.Interval, err = time.ParseDuration(v.Interval); err != nil {
+			return err
+		}
+	}
+	if v.Error != "" {
+		gi.Err = errors.New(v.Error)
+	}
+	if v.Panic != nil {
+		gi.Panic = v.Panic.Value
+		frames := make([]Frame, 0, len(v.Panic.Stack))
+		for _, fr := range v.Panic.Stack {
+			frames = append(frames, Frame{Function: fr.Func, File: fr.File, Line: fr.Line, PC: fr.PC})
+		}
+		gi.PanicStack, gi.PanicFrames = formatFrames(frames), frames
+	}
+	return nil
+}
+
+type causeJSON struct {
+	Error string `json:"error"`
+	Type  string `json:"type"`
+}
+
+type exitInfoJSON struct {
+	Canceler     Canceler    `json:"canceler"`
+	Cause        string      `json:"cause,omitempty"`
+	Causes       []causeJSON `json:"causes,omitempty"` // Cause flattened by UnwrapMultiError
+	FirstUseLine string      `json:"first_use_line,omitempty"`
+	FirstUseTime *time.Time  `json:"first_use_time,omitempty"`
+	FirstGoTime  *time.Time  `json:"first_go_time,omitempty"`
+	CancelTime   *time.Time  `json:"cancel_time,omitempty"`
+	ExitTime     *time.Time  `json:"exit_time,omitempty"`
+	Goroutines   []GoInfo    `json:"goroutines"`
+	Phases       []PhaseInfo `json:"phases,omitempty"`
+	Children     []*ExitInfo `json:"children,omitempty"`
+}
+
+func (ei ExitInfo) MarshalJSON() ([]byte, error) {
+	v := exitInfoJSON{
+		Canceler:     ei.Canceler(),
+		FirstUseLine: ei.FirstUseLine,
+		FirstGoTime:  ei.FirstGoTime,
+		CancelTime:   ei.CancelTime,
+		ExitTime:     ei.ExitTime,
+		Goroutines:   ei.GoInfos,
+		Phases:       ei.Phases,
+		Children:     ei.Children,
+	}
+	if v.Goroutines == nil {
+		v.Goroutines = []GoInfo{}
+	}
+	if !ei.FirstUseTime.IsZero() {
+		v.FirstUseTime = &ei.FirstUseTime
+	}
+	if ei.Cause != nil {
+		v.Cause = ei.Cause.Error()
+		for _, err := range flattenError(ei.Cause) {
+			v.Causes = append(v.Causes, causeJSON{Error: err.Error(), Type: fmt.Sprintf("%T", err)})
+		}
+	}
+	return json.Marshal(v)
+}
+
+func (ei *ExitInfo) UnmarshalJSON(bs []byte) error {
+	var v exitInfoJSON
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	*ei = ExitInfo{
+		GoInfos:              v.Goroutines,
+		CancelByUser:         v.Canceler == CancelerUser,
+		CancelByRootContext:  v.Canceler == CancelerRootContext,
+		CancelBySubGoroutine: v.Canceler == CancelerSubGoroutine,
+		FirstUseLine:         v.FirstUseLine,
+		FirstGoTime:          v.FirstGoTime,
+		CancelTime:           v.CancelTime,
+		ExitTime:             v.ExitTime,
+		Phases:               v.Phases,
+		Children:             v.Children,
+	}
+	if v.FirstUseTime != nil {
+		ei.FirstUseTime = *v.FirstUseTime
+	}
+	if len(v.Causes) > 1 {
+		je := &jsonError{msg: v.Cause}
+		for _, c := range v.Causes {
+			je.errs = append(je.errs, errors.New(c.Error))
+		}
+		ei.Cause = je
+	} else if v.Cause != "" {
+		ei.Cause = errors.New(v.Cause)
+	}
+	return nil
+}
+
+type runningInfoJSON struct {
+	Func        FuncInfo  `json:"func"`
+	GoroutineID int64     `json:"goroutine_id"`
+	StartTime   time.Time `json:"start_time"`
+	Uptime      string    `json:"uptime"`
+	Attempt     int       `json:"attempt,omitempty"`
+	Interval    string    `json:"interval,omitempty"`
+}
+
+type snapshotJSON struct {
+	Time         time.Time         `json:"time"`
+	State        GroupState        `json:"state"`
+	Canceler     Canceler          `json:"canceler"`
+	Cause        string            `json:"cause,omitempty"`
+	CancelTime   *time.Time        `json:"cancel_time,omitempty"`
+	FirstUseLine string            `json:"first_use_line,omitempty"`
+	FirstUseTime *time.Time        `json:"first_use_time,omitempty"`
+	Running      []runningInfoJSON `json:"running"`
+	Exited       []GoInfo          `json:"exited"`
+}
+
+// MarshalJSON of Snapshot is for debug pages, there is no UnmarshalJSON
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	v := snapshotJSON{
+		Time:         s.Time,
+		State:        s.State,
+		Canceler:     s.Canceler,
+		CancelTime:   s.CancelTime,
+		FirstUseLine: s.FirstUseLine,
+		Running:      []runningInfoJSON{},
+		Exited:       s.Exited,
+	}
+	if v.Exited == nil {
+		v.Exited = []GoInfo{}
+	}
+	if s.Cause != nil {
+		v.Cause = s.Cause.Error()
+	}
+	if !s.FirstUseTime.IsZero() {
+		v.FirstUseTime = &s.FirstUseTime
+	}
+	for _, ri := range s.Running {
+		rj := runningInfoJSON{
+			Func:        ri.FuncInfo,
+			GoroutineID: ri.GoroutineID,
+			StartTime:   ri.StartTime,
+			Uptime:      ri.Uptime.String(),
+			Attempt:     ri.Attempt,
+		}
+		if ri.Interval > 0 {
+			rj.Interval = ri.Interval.String()
+		}
+		v.Running = append(v.Running, rj)
+	}
+	return json.Marshal(v)
+}
+
+func (s GroupState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Canceler returns who canceled the group
+func (ei ExitInfo) Canceler() Canceler {
+	switch {
+	case ei.CancelByUser:
+		return CancelerUser
+	case ei.CancelByRootContext:
+		return CancelerRootContext
+	case ei.CancelBySubGoroutine:
+		return CancelerSubGoroutine
+	}
+	return CancelerNone
+}
+
+func (c Canceler) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Canceler) UnmarshalText(bs []byte) error {
+	for _, v := range []Canceler{CancelerNone, CancelerUser, CancelerRootContext, CancelerSubGoroutine} {
+		if v.String() == string(bs) {
+			*c = v
+			return nil
+		}
+	}
+	return fmt.Errorf("gogroup: unknown Canceler %q", bs)
+}
+
+// flattenError unwraps multi error recursively by UnwrapMultiError
+func flattenError(err error) []error {
+	errs := UnwrapMultiError(err)
+	if len(errs) == 1 && errs[0] == err {
+		return errs
+	}
+	var flat []error
+	for _, e := range errs {
+		if e != nil {
+			flat = append(flat, flattenError(e)...)
+		}
+	}
+	return flat
+}
+
+// jsonError is Cause of unmarshalled ExitInfo if Cause is a multi error, it keeps the message and the flattened errors
+type jsonError struct {
+	msg  string
+	errs []error
+}
+
+func (e *jsonError) Error() string {
+	return e.msg
+}
+
+func (e *jsonError) Unwrap() []error {
+	return e.errs
+}
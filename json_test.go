@@ -0,0 +1,121 @@
+package gogroup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExitInfoJSON(t *testing.T) {
+	var g Group
+	g.GoErr(func(ctx context.Context) error {
+		return errors.New("json err")
+	})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		panic("json panic")
+	})
+	g.Wait()
+	ei := g.ExitInfo()
+	bs, err := json.Marshal(ei)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(bs)
+	for _, v := range []string{
+		`"canceler":"CancelBySubGoroutine"`,
+		`"error":"json err"`,
+		`"value":"json panic","type":"string"`,
+		`"func":"github.com/xiaotushaoxia/gogroup.TestExitInfoJSON.func2"`,
+		`"duration":"`,
+		`"exit_time":"`,
+	} {
+		if !strings.Contains(s, v) {
+			t.Fatal("json not contain", v, "\n", s)
+		}
+	}
+
+	var ei2 ExitInfo
+	if err = json.Unmarshal(bs, &ei2); err != nil {
+		t.Fatal(err)
+	}
+	if !ei2.CancelBySubGoroutine || ei2.Cause.Error() != ei.Cause.Error() || len(ei2.GoInfos) != 2 {
+		t.Fatal("unmarshal not right", ei2)
+	}
+	if !ei2.ExitTime.Equal(*ei.ExitTime) || !ei2.FirstUseTime.Equal(ei.FirstUseTime) {
+		t.Fatal("time not right", ei2)
+	}
+	for i, gi := range ei2.GoInfos {
+		if gi.FuncInfo != ei.GoInfos[i].FuncInfo || (gi.Err == nil) != (ei.GoInfos[i].Err == nil) {
+			t.Fatal("GoInfo not right", gi, ei.GoInfos[i])
+		}
+		if gi.Panic != nil && !strings.Contains(string(gi.PanicStack), "TestExitInfoJSON.func2") {
+			t.Fatal("PanicStack not right", string(gi.PanicStack))
+		}
+	}
+	bs2, err := json.Marshal(ei2.GoInfos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s, string(bs2)) {
+		t.Fatal("marshal is not stable\n", s, "\n", string(bs2))
+	}
+}
+
+func TestExitInfoJSONCauses(t *testing.T) {
+	ei := ExitInfo{CancelByUser: true, Cause: errors.Join(errors.New("a"), errors.Join(errors.New("b"), errors.New("c")))}
+	bs, err := json.Marshal(ei)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ei2 ExitInfo
+	if err = json.Unmarshal(bs, &ei2); err != nil {
+		t.Fatal(err)
+	}
+	causes := UnwrapMultiError(ei2.Cause)
+	if len(causes) != 3 || causes[2].Error() != "c" || ei2.Cause.Error() != "a\nb\nc" || !ei2.CancelByUser {
+		t.Fatal("causes not right", string(bs))
+	}
+	if !strings.Contains(string(bs), `"goroutines":[]`) {
+		t.Fatal("goroutines should not be null", string(bs))
+	}
+}
+
+func TestGoInfoJSON(t *testing.T) {
+	now := time.Now()
+	gi := GoInfo{
+		FuncInfo:  FuncInfo{FuncName: "f", File: "a.go", Line: 1, Description: "d"},
+		StartTime: now,
+		ExitTime:  now.Add(time.Second + time.Millisecond*500),
+		QueueWait: time.Millisecond,
+		Attempt:   2,
+	}
+	bs, err := json.Marshal(gi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bs), `"func":{"func":"f","file":"a.go","line":1,"desc":"d"}`) ||
+		!strings.Contains(string(bs), `"duration":"1.5s","queue_wait":"1ms","attempt":2`) {
+		t.Fatal("json not right", string(bs))
+	}
+	var gi2 GoInfo
+	if err = json.Unmarshal(bs, &gi2); err != nil {
+		t.Fatal(err)
+	}
+	if gi2.FuncInfo != gi.FuncInfo || gi2.QueueWait != gi.QueueWait || !gi2.ExitTime.Equal(gi.ExitTime) {
+		t.Fatal("unmarshal not right", gi2)
+	}
+}
+
+func TestCancelerText(t *testing.T) {
+	var c Canceler
+	if err := c.UnmarshalText([]byte("CancelByRootContext")); err != nil || c != CancelerRootContext {
+		t.Fatal("UnmarshalText not right", c, err)
+	}
+	if err := c.UnmarshalText([]byte("xx")); err == nil {
+		t.Fatal("UnmarshalText should fail")
+	}
+}
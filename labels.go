@@ -0,0 +1,39 @@
+package gogroup
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// pprof labels of goroutines started by group, use them with `go tool pprof -tagfocus`
+const (
+	LabelFunc  = "gogroup.func"  // FuncInfo.FuncName
+	LabelDesc  = "gogroup.desc"  // FuncInfo.Description, if not empty
+	LabelGroup = "gogroup.group" // name of group, if set. see Group.SetName
+)
+
+func (g *groupBase) setName(name string) {
+	g.name.Store(&name)
+}
+
+func (g *groupBase) getName() string {
+	if p := g.name.Load(); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// doWithLabels calls f under pprof labels of fi
+func (g *groupBase) doWithLabels(ctx context.Context, fi FuncInfo, f func(context.Context) error) (err error) {
+	kvs := []string{LabelFunc, fi.FuncName}
+	if fi.Description != "" {
+		kvs = append(kvs, LabelDesc, fi.Description)
+	}
+	if name := g.getName(); name != "" {
+		kvs = append(kvs, LabelGroup, name)
+	}
+	pprof.Do(ctx, pprof.Labels(kvs...), func(ctx context.Context) {
+		err = f(ctx)
+	})
+	return
+}
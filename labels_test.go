@@ -0,0 +1,64 @@
+package gogroup
+
+import (
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+func testPprofLabels(t *testing.T, g interface {
+	GoGroup
+	SetName(string)
+	Name() string
+	GoWithFuncInfo(func(context.Context), FuncInfo)
+}) {
+	g.SetName("ingest")
+	if g.Name() != "ingest" {
+		t.Fatal("Name not right", g.Name())
+	}
+	labels := make(chan map[string]string, 2)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		m := map[string]string{}
+		pprof.ForLabels(ctx, func(k, v string) bool {
+			m[k] = v
+			return true
+		})
+		labels <- m
+	})
+	g.GoWithFuncInfo(func(ctx context.Context) {
+		m := map[string]string{}
+		pprof.ForLabels(ctx, func(k, v string) bool {
+			m[k] = v
+			return true
+		})
+		labels <- m
+	}, FuncInfo{FuncName: "loop", Description: "main loop"})
+	g.Wait()
+	for i := 0; i < 2; i++ {
+		m := <-labels
+		if m[LabelGroup] != "ingest" {
+			t.Fatal("group label not right", m)
+		}
+		if m[LabelFunc] == "loop" {
+			if m[LabelDesc] != "main loop" {
+				t.Fatal("desc label not right", m)
+			}
+		} else if !strings.Contains(m[LabelFunc], "testPprofLabels.func1") {
+			t.Fatal("func label not right", m)
+		} else if _, ok := m[LabelDesc]; ok {
+			t.Fatal("desc label should not set", m)
+		}
+	}
+}
+
+func TestGroupPprofLabels(t *testing.T) {
+	var g Group
+	testPprofLabels(t, &g)
+}
+
+func TestMiniGroupPprofLabels(t *testing.T) {
+	var g MiniGroup
+	testPprofLabels(t, &g)
+}
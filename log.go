@@ -0,0 +1,123 @@
+package gogroup
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LogValue makes FuncInfo an attribute group of slog
+func (fi FuncInfo) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("name", fi.FuncName),
+		slog.String("file", fi.File),
+		slog.Int("line", fi.Line),
+	}
+	if fi.Description != "" {
+		attrs = append(attrs, slog.String("desc", fi.Description))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func (g *groupBase) setLogger(l *slog.Logger) {
+	g.logger.Store(l)
+}
+
+func (g *groupBase) log(level slog.Level, msg string, attrs ...slog.Attr) {
+	l := g.logger.Load()
+	if l == nil {
+		return
+	}
+	l.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (g *groupBase) logGoStart(fi FuncInfo) {
+	g.log(slog.LevelDebug, "gogroup: goroutine start", slog.Any("func", fi))
+}
+
+func (g *groupBase) logGoExit(ei GoInfo) {
+	attrs := []slog.Attr{
+		slog.Any("func", ei.FuncInfo),
+		slog.Duration("uptime", ei.ExitTime.Sub(ei.StartTime)),
+	}
+	if ei.Attempt > 1 {
+		attrs = append(attrs, slog.Int("attempt", ei.Attempt))
+	}
+	if ei.Panic != nil {
+		attrs = append(attrs, slog.Any("panic", ei.Panic), slog.String("stack", string(ei.PanicStack)))
+		g.log(slog.LevelError, "gogroup: goroutine panic", attrs...)
+		return
+	}
+	if ei.Err != nil {
+		attrs = append(attrs, slog.Any("err", ei.Err))
+		g.log(slog.LevelWarn, "gogroup: goroutine exit", attrs...)
+		return
+	}
+	g.log(slog.LevelInfo, "gogroup: goroutine exit", attrs...)
+}
+
+func (g *groupBase) logCancel(cause error, canceler Canceler) {
+	g.log(slog.LevelInfo, "gogroup: group canceled",
+		slog.String("canceler", canceler.String()), slog.Any("cause", cause))
+}
+
+// SetLogger sets the logger of Group lifecycle events. nil disables logging (default).
+// first Go, goroutine start(debug), exit, panic(error, with stack), cancel and group exit are logged
+func (g *Group) SetLogger(l *slog.Logger) {
+	g.setLogger(l)
+}
+
+func (g *Group) logFirstUse() {
+	g.log(slog.LevelInfo, "gogroup: group start",
+		slog.String("first_use_line", g.firstUseLine), slog.Time("first_use_time", g.firstUseTime))
+}
+
+func (g *Group) logExit() {
+	g.exitsM.Lock()
+	n := len(g.exits)
+	g.exitsM.Unlock()
+	g.log(slog.LevelInfo, "gogroup: group exit",
+		slog.String("first_use_line", g.firstUseLine),
+		slog.String("canceler", Canceler(g.cancelFlag.Load()).String()),
+		slog.Any("cause", context.Cause(g.ctx)),
+		slog.Int("goroutines", n))
+}
+
+// SetLogger sets the logger of MiniGroup lifecycle events. nil disables logging (default).
+// first Go, goroutine start(debug), exit, panic(error, with stack), cancel and group exit are logged
+func (g *MiniGroup) SetLogger(l *slog.Logger) {
+	g.setLogger(l)
+}
+
+// cancelBy cancels g with err, and logs it if it is the first cancel
+func (g *MiniGroup) cancelBy(err error, canceler Canceler) {
+	if isContextDone(g.ctx) {
+		return
+	}
+	if !g.canceler.CompareAndSwap(int32(CancelerNone), int32(canceler)) {
+		return
+	}
+	now := time.Now()
+	g.cancelAt.Store(&now)
+	g.cancelCause(err)
+	g.logCancel(err, canceler)
+	g.callOnCancel(err, canceler)
+}
+
+// recordExit logs the exit and calls hooks, MiniGroup keeps no GoInfo
+func (g *MiniGroup) recordExit(fi FuncInfo, start time.Time, p any, pe *PanicError, err error) GoInfo {
+	ei := GoInfo{
+		FuncInfo:  fi,
+		Panic:     p,
+		StartTime: start,
+		ExitTime:  time.Now(),
+		Err:       err,
+	}
+	if pe != nil {
+		ei.PanicStack, ei.PanicFrames = pe.Stack, pe.Frames
+	}
+	g.logGoExit(ei)
+	g.countExit(ei)
+	g.callOnGoExit(ei)
+	return ei
+}
@@ -0,0 +1,65 @@
+package gogroup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func testLogger(t *testing.T, g interface {
+	GoGroup
+	SetLogger(*slog.Logger)
+}) string {
+	var buf bytes.Buffer
+	g.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.Go(func(ctx context.Context) {
+		panic("logged")
+	})
+	g.Wait()
+	s := buf.String()
+	for _, msg := range []string{
+		"gogroup: group start",
+		"gogroup: goroutine start",
+		"gogroup: goroutine exit",
+		"gogroup: goroutine panic",
+		"gogroup: group canceled",
+		"gogroup: group exit",
+		"canceler=CancelBySubGoroutine",
+		"panic=logged",
+		"func.name=github.com/xiaotushaoxia/gogroup.testLogger.func",
+	} {
+		if !strings.Contains(s, msg) {
+			t.Fatal("log not contain", msg, "\n", s)
+		}
+	}
+	return s
+}
+
+func TestGroupSetLogger(t *testing.T) {
+	var g Group
+	s := testLogger(t, &g)
+	if !strings.Contains(s, "first_use_line=") {
+		t.Fatal("log not contain first_use_line\n", s)
+	}
+	fmt.Println(s)
+}
+
+func TestMiniGroupSetLogger(t *testing.T) {
+	var g MiniGroup
+	testLogger(t, &g)
+}
+
+func TestFuncInfoLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil))
+	l.Info("x", "func", FuncInfo{FuncName: "f", File: "a.go", Line: 1, Description: "d"})
+	if !strings.Contains(buf.String(), "func.name=f func.file=a.go func.line=1 func.desc=d") {
+		t.Fatal("LogValue not right", buf.String())
+	}
+}
@@ -0,0 +1,259 @@
+package gogroup
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// default buckets of Metrics histograms, in seconds
+var (
+	DefaultLifetimeBuckets = []float64{0.01, 0.1, 1, 10, 60, 600, 3600, 6 * 3600, 24 * 3600}
+	DefaultTickBuckets     = []float64{0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+)
+
+// Metrics collects metrics of groups by Hooks, and writes them in Prometheus text format.
+// it has no dependencies, see sub package promadapter for prometheus/client_golang. e.g.
+//
+//	m := gogroup.NewMetrics()
+//	g.AddHooks(m.Hooks("ingest"))
+//	http.Handle("/metrics", m)
+type Metrics struct {
+	lifetimeBuckets []float64
+	tickBuckets     []float64
+
+	m         sync.Mutex
+	started   map[metricKey]uint64
+	exited    map[metricKey]uint64
+	panicked  map[metricKey]uint64
+	running   map[string]int64 // by group
+	lifetimes map[metricKey]*histogram
+	ticks     map[metricKey]*histogram
+	cancels   map[metricKey]uint64 // func of key is canceler
+}
+
+type metricKey struct {
+	group, fn string
+}
+
+type histogram struct {
+	counts []uint64 // counts[i] is number of values <= buckets[i], not cumulative
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	if i := sort.SearchFloat64s(buckets, v); i < len(buckets) {
+		h.counts[i]++
+	}
+	h.sum += v
+	h.count++
+}
+
+// NewMetrics returns Metrics with DefaultLifetimeBuckets and DefaultTickBuckets
+func NewMetrics() *Metrics {
+	return NewMetricsWithBuckets(DefaultLifetimeBuckets, DefaultTickBuckets)
+}
+
+// NewMetricsWithBuckets returns Metrics with histogram buckets in seconds, buckets must be sorted
+func NewMetricsWithBuckets(lifetime, tick []float64) *Metrics {
+	return &Metrics{
+		lifetimeBuckets: lifetime,
+		tickBuckets:     tick,
+		started:         make(map[metricKey]uint64),
+		exited:          make(map[metricKey]uint64),
+		panicked:        make(map[metricKey]uint64),
+		running:         make(map[string]int64),
+		lifetimes:       make(map[metricKey]*histogram),
+		ticks:           make(map[metricKey]*histogram),
+		cancels:         make(map[metricKey]uint64),
+	}
+}
+
+// Hooks returns Hooks that collect metrics of a group with label group="name", add it by AddHooks
+func (m *Metrics) Hooks(group string) Hooks {
+	return &metricsHooks{m: m, group: group}
+}
+
+type metricsHooks struct {
+	m     *Metrics
+	group string
+}
+
+func (h *metricsHooks) OnGoStart(fi FuncInfo) {
+	h.m.m.Lock()
+	h.m.started[metricKey{h.group, fi.FuncName}]++
+	h.m.running[h.group]++
+	h.m.m.Unlock()
+}
+
+func (h *metricsHooks) OnGoExit(gi GoInfo) {
+	k := metricKey{h.group, gi.FuncInfo.FuncName}
+	h.m.m.Lock()
+	h.m.exited[k]++
+	h.m.running[h.group]--
+	h.m.histogram(h.m.lifetimes, k, h.m.lifetimeBuckets).observe(h.m.lifetimeBuckets, gi.ExitTime.Sub(gi.StartTime).Seconds())
+	h.m.m.Unlock()
+}
+
+func (h *metricsHooks) OnPanic(gi GoInfo) {
+	h.m.m.Lock()
+	h.m.panicked[metricKey{h.group, gi.FuncInfo.FuncName}]++
+	h.m.m.Unlock()
+}
+
+func (h *metricsHooks) OnCancel(cause error, canceler Canceler) {
+	h.m.m.Lock()
+	h.m.cancels[metricKey{h.group, cancelerLabel(canceler)}]++
+	h.m.m.Unlock()
+}
+
+func (h *metricsHooks) OnGroupExit(*ExitInfo) {}
+
+func (h *metricsHooks) OnTick(fi FuncInfo, d time.Duration) {
+	k := metricKey{h.group, fi.FuncName}
+	h.m.m.Lock()
+	h.m.histogram(h.m.ticks, k, h.m.tickBuckets).observe(h.m.tickBuckets, d.Seconds())
+	h.m.m.Unlock()
+}
+
+// histogram must be called with m.m locked
+func (m *Metrics) histogram(hs map[metricKey]*histogram, k metricKey, buckets []float64) *histogram {
+	h := hs[k]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(buckets))}
+		hs[k] = h
+	}
+	return h
+}
+
+func cancelerLabel(c Canceler) string {
+	switch c {
+	case CancelerUser:
+		return "user"
+	case CancelerRootContext:
+		return "root_context"
+	case CancelerSubGoroutine:
+		return "sub_goroutine"
+	}
+	return "unknown"
+}
+
+// ServeHTTP writes metrics in Prometheus text format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+// WriteTo writes metrics in Prometheus text format 0.0.4
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: bufio.NewWriter(w)}
+	m.m.Lock()
+	m.writeCounter(cw, "gogroup_goroutines_started_total", "Goroutines started by group, including restarts.", "func", m.started)
+	m.writeCounter(cw, "gogroup_goroutines_exited_total", "Goroutines exited, including panics.", "func", m.exited)
+	m.writeCounter(cw, "gogroup_goroutines_panicked_total", "Goroutines panicked.", "func", m.panicked)
+	cw.header("gogroup_goroutines_running", "Goroutines running in group.", "gauge")
+	groups := make([]string, 0, len(m.running))
+	for group := range m.running {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		cw.sample("gogroup_goroutines_running", labels("group", group), strconv.FormatInt(m.running[group], 10))
+	}
+	m.writeCounter(cw, "gogroup_cancellations_total", "Group cancellations by canceler.", "canceler", m.cancels)
+	m.writeHistogram(cw, "gogroup_goroutine_lifetime_seconds", "Lifetime of goroutines.", m.lifetimeBuckets, m.lifetimes)
+	m.writeHistogram(cw, "gogroup_tick_duration_seconds", "Duration of each tick of GoTk.", m.tickBuckets, m.ticks)
+	m.m.Unlock()
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+func (m *Metrics) writeCounter(cw *countWriter, name, help, label string, vs map[metricKey]uint64) {
+	cw.header(name, help, "counter")
+	for _, k := range sortedKeys(vs) {
+		cw.sample(name, labels("group", k.group, label, k.fn), strconv.FormatUint(vs[k], 10))
+	}
+}
+
+func (m *Metrics) writeHistogram(cw *countWriter, name, help string, buckets []float64, hs map[metricKey]*histogram) {
+	cw.header(name, help, "histogram")
+	for _, k := range sortedKeys(hs) {
+		h := hs[k]
+		var cum uint64
+		for i, b := range buckets {
+			cum += h.counts[i]
+			cw.sample(name+"_bucket", labels("group", k.group, "func", k.fn, "le", formatFloat(b)), strconv.FormatUint(cum, 10))
+		}
+		cw.sample(name+"_bucket", labels("group", k.group, "func", k.fn, "le", "+Inf"), strconv.FormatUint(h.count, 10))
+		cw.sample(name+"_sum", labels("group", k.group, "func", k.fn), formatFloat(h.sum))
+		cw.sample(name+"_count", labels("group", k.group, "func", k.fn), strconv.FormatUint(h.count, 10))
+	}
+}
+
+func sortedKeys[V any](m map[metricKey]V) []metricKey {
+	ks := make([]metricKey, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Slice(ks, func(i, j int) bool {
+		return ks[i].group < ks[j].group || ks[i].group == ks[j].group && ks[i].fn < ks[j].fn
+	})
+	return ks
+}
+
+// labels formats name value pairs to {n1="v1",n2="v2"}
+func labels(kvs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(kvs[i])
+		b.WriteString(`="`)
+		b.WriteString(labelEscaper.Replace(kvs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countWriter writes metrics and keeps the first error
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) write(s ...string) {
+	for _, v := range s {
+		if cw.err != nil {
+			return
+		}
+		n, err := cw.w.WriteString(v)
+		cw.n += int64(n)
+		cw.err = err
+	}
+}
+
+func (cw *countWriter) header(name, help, typ string) {
+	cw.write("# HELP ", name, " ", help, "\n# TYPE ", name, " ", typ, "\n")
+}
+
+func (cw *countWriter) sample(name, labels, value string) {
+	cw.write(name, labels, " ", value, "\n")
+}
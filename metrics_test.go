@@ -0,0 +1,79 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	var g Group
+	g.AddHooks(m.Hooks("ingest"))
+	g.GoWithFuncInfo(func(ctx context.Context) {
+		<-ctx.Done()
+	}, FuncInfo{FuncName: `loop"1"`})
+	g.GoTkWithFuncInfo(func() {}, time.Millisecond*5, FuncInfo{FuncName: "tick"})
+	g.GoWithRestart(func(ctx context.Context) {
+		time.Sleep(time.Millisecond * 10)
+		panic("restart")
+	}, RestartPolicy{Mode: RestartOnPanic, MaxRestarts: 2})
+	g.Wait()
+
+	mg := NewMini(context.Background())
+	mg.AddHooks(m.Hooks("mini"))
+	mg.GoWithFuncInfo(func(ctx context.Context) {
+		<-ctx.Done()
+	}, FuncInfo{FuncName: "mini"})
+	mg.Cancel(errors.New("stop"))
+	mg.Wait()
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	bs, _ := io.ReadAll(rec.Body)
+	s := string(bs)
+	restart := "github.com/xiaotushaoxia/gogroup.TestMetrics.func3"
+	for _, v := range []string{
+		"# TYPE gogroup_goroutines_started_total counter\n",
+		`gogroup_goroutines_started_total{group="ingest",func="` + restart + `"} 3` + "\n",
+		`gogroup_goroutines_exited_total{group="ingest",func="` + restart + `"} 3` + "\n",
+		`gogroup_goroutines_panicked_total{group="ingest",func="` + restart + `"} 3` + "\n",
+		`gogroup_goroutines_exited_total{group="ingest",func="loop\"1\""} 1` + "\n",
+		`gogroup_goroutines_running{group="ingest"} 0` + "\n",
+		`gogroup_goroutines_running{group="mini"} 0` + "\n",
+		`gogroup_cancellations_total{group="ingest",canceler="sub_goroutine"} 1` + "\n",
+		`gogroup_cancellations_total{group="mini",canceler="user"} 1` + "\n",
+		"# TYPE gogroup_goroutine_lifetime_seconds histogram\n",
+		`gogroup_goroutine_lifetime_seconds_bucket{group="ingest",func="tick",le="+Inf"} 1` + "\n",
+		`gogroup_goroutine_lifetime_seconds_count{group="mini",func="mini"} 1` + "\n",
+		`gogroup_tick_duration_seconds_bucket{group="ingest",func="tick",le="0.0005"} `,
+	} {
+		if !strings.Contains(s, v) {
+			t.Fatal("metrics not contain", v, "\n", s)
+		}
+	}
+	if rec.Header().Get("Content-Type") != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Fatal("Content-Type not right", rec.Header())
+	}
+}
+
+func TestMetricsRunning(t *testing.T) {
+	m := NewMetrics()
+	var g Group
+	g.AddHooks(m.Hooks("running"))
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	var buf strings.Builder
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `gogroup_goroutines_running{group="running"} 1`) {
+		t.Fatal("running not right", buf.String())
+	}
+	g.CancelAndWait(nil)
+}
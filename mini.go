@@ -2,22 +2,30 @@ package gogroup
 
 import (
 	"context"
-	"errors"
-	"fmt"
+	"log/slog"
 	"sync/atomic"
 	"time"
 )
 
-func NewMini(ctx context.Context) *MiniGroup {
+func NewMini(ctx context.Context, opts ...Option) *MiniGroup {
 	g := &MiniGroup{groupBase: groupBase{root: ctx}}
 	g.init()
+	if o := applyOptions(opts); o.name != "" {
+		g.SetName(o.name)
+		Register(g)
+	}
 	return g
 }
 
 type MiniGroup struct {
 	groupBase
 	watcher
-	exited atomic.Bool
+	exited  atomic.Bool
+	started atomic.Bool
+	running atomic.Int32
+
+	canceler atomic.Int32 // see Canceler
+	cancelAt atomic.Pointer[time.Time]
 }
 
 func (g *MiniGroup) Go(f func(ctx context.Context)) {
@@ -27,12 +35,12 @@ func (g *MiniGroup) Go(f func(ctx context.Context)) {
 
 func (g *MiniGroup) GoTk(f func(), d time.Duration) {
 	g.init()
-	g.goWithFuncInfo(toTkFunc(f, d), ParserFuncInfo(f))
+	g.goWithSpec(toErrFunc(toTkFunc(f, d)), goSpec{fi: ParserFuncInfo(f), interval: d})
 }
 
 func (g *MiniGroup) GoTkWithFuncInfo(f func(), d time.Duration, fi FuncInfo) {
 	g.init()
-	g.goWithFuncInfo(toTkFunc(f, d), fi)
+	g.goWithSpec(toErrFunc(toTkFunc(f, d)), goSpec{fi: fi, interval: d})
 }
 
 func (g *MiniGroup) GoWithFuncInfo(f func(context.Context), fi FuncInfo) {
@@ -40,9 +48,70 @@ func (g *MiniGroup) GoWithFuncInfo(f func(context.Context), fi FuncInfo) {
 	g.goWithFuncInfo(f, fi)
 }
 
+// SetPanicPolicy sets what to do after a goroutine panics. see PanicPolicy
+func (g *MiniGroup) SetPanicPolicy(p PanicPolicy) {
+	g.setPanicPolicy(p)
+}
+
+// GoWithPanicPolicy same as Go, but p overrides PanicPolicy of MiniGroup for f
+func (g *MiniGroup) GoWithPanicPolicy(f func(ctx context.Context), p PanicPolicy) {
+	g.init()
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), panicPolicy: &p})
+}
+
+// SetName sets name of MiniGroup, it is used as pprof label LabelGroup of goroutines started after it
+func (g *MiniGroup) SetName(name string) {
+	g.setName(name)
+}
+
+func (g *MiniGroup) Name() string {
+	return g.getName()
+}
+
+// AddHooks registers h to observe lifecycle of MiniGroup. see Hooks
+// OnGroupExit of MiniGroup only gets the Cause
+func (g *MiniGroup) AddHooks(h Hooks) {
+	g.addHooks(h)
+}
+
+// TryGo same as Go, but returns false without starting f if the limit is reached. see GoGroupExt.SetLimit
+func (g *MiniGroup) TryGo(f func(context.Context)) bool {
+	g.init()
+	g.panicIfExited()
+	sem, ok := g.tryAcquire()
+	if !ok {
+		return false
+	}
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), acquired: true, sem: sem})
+	return true
+}
+
+// SetLimit limits the number of running goroutines. see GoGroupExt.SetLimit
+func (g *MiniGroup) SetLimit(n int) {
+	g.setLimit(n)
+}
+
+// GoOptional start a non-critical goroutine. see GoGroupExt.GoOptional
+func (g *MiniGroup) GoOptional(f func(context.Context), ignorePanic bool) {
+	g.init()
+	g.goWithSpec(toErrFunc(f), goSpec{fi: ParserFuncInfo(f), optional: true, ignorePanic: ignorePanic})
+}
+
+// GoErr same as Go, but f returns an error. see GoGroupExt.GoErr
+func (g *MiniGroup) GoErr(f func(context.Context) error) {
+	g.init()
+	g.goWithSpec(f, goSpec{fi: ParserFuncInfo(f)})
+}
+
+// GoTkErr same as GoTk, but f returns an error. see GoGroupExt.GoTkErr
+func (g *MiniGroup) GoTkErr(f func() error, d time.Duration) {
+	g.init()
+	g.goWithSpec(toTkErrFunc(f, d), goSpec{fi: ParserFuncInfo(f), interval: d})
+}
+
 func (g *MiniGroup) CancelAndWait(err error) {
 	g.init()
-	g.cancelCause(err)
+	g.cancelBy(err, CancelerUser)
 	<-g.watch().Done()
 }
 
@@ -58,7 +127,7 @@ func (g *MiniGroup) Wait() {
 
 func (g *MiniGroup) Cancel(err error) {
 	g.init()
-	g.cancelCause(err)
+	g.cancelBy(err, CancelerUser)
 }
 
 func (g *MiniGroup) Err() error {
@@ -81,31 +150,91 @@ func (g *MiniGroup) init() {
 }
 
 func (g *MiniGroup) waitAndSetExit() {
+	// see Group.waitAndSetExit
+	g.waiting.Store(true)
+	if g.running.Load() == 0 && g.started.Load() {
+		g.cancelBy(ErrAllExited, CancelerSubGoroutine)
+	}
 	g.wg.Wait()
 	g.exited.Store(true)
+	g.finishExit(false)
+	g.exiting.Wait() // see Group.waitAndSetExit
+}
+
+// finishExit is called once g exited, by f5 or by exitIfDone (auto)
+func (g *MiniGroup) finishExit(auto bool) {
+	if !g.markExited(&g.running, auto, func() { registryExited(g) }) {
+		return
+	}
+	defer g.exiting.Done()
+	g.log(slog.LevelInfo, "gogroup: group exit", slog.Any("cause", context.Cause(g.ctx)))
+	g.callOnGroupExit(&ExitInfo{Cause: context.Cause(g.ctx)})
+}
+
+// reopen undoes finishExit without f5, see Group.reopen
+func (g *MiniGroup) reopen() {
+	registryReopened(g)
 }
 
 func (g *MiniGroup) goWithFuncInfo(f func(context.Context), fi FuncInfo) {
-	if g.exited.Load() {
-		panic("group is exited")
+	g.goWithSpec(toErrFunc(f), goSpec{fi: fi})
+}
+
+func (g *MiniGroup) goWithSpec(f func(context.Context) error, spec goSpec) {
+	g.panicIfExited()
+	g.acquire(&spec)
+	first := g.started.CompareAndSwap(false, true)
+	if first {
+		g.log(slog.LevelInfo, "gogroup: group start")
+	}
+	g.addRunning(&g.running, g.reopen)
+	if first {
+		context.AfterFunc(g.ctx, g.exitIfDone)
 	}
 	g.wg.Add(1)
+	g.logGoStart(spec.fi)
+	g.callOnGoStart(spec.fi)
+	start := time.Now()
 	go func() {
-		defer g.handleExit(fi)
-		f(g.ctx)
+		var err error
+		defer g.handleExit(spec, start, &err)
+		err = g.doWithLabels(g.tickContext(g.ctx, spec), spec.fi, f)
 	}()
 }
 
-func (g *MiniGroup) handleExit(fi FuncInfo) {
+func (g *MiniGroup) panicIfExited() {
+	if g.exited.Load() {
+		panic("group is exited")
+	}
+}
+
+// handleExit must be deferred directly, err points to the error returned by goroutine
+func (g *MiniGroup) handleExit(spec goSpec, start time.Time, err *error) {
+	defer g.wg.Done()
+	idle := g.running.Add(-1) == 0 && g.waiting.Load()
+	g.notifyExit()
+	g.release(spec)
 	if p := recover(); p != nil {
-		st := stack(4)
-		gid := parserGoroutineInStack(st)
-		tail := gid + fmt.Sprintf(": panic(%v) exit. \nstack: %s", p, string(st))
-		g.cancelCause(errors.New(fi.String() + ": " + tail))
+		pe := newPanicError(spec.fi, p, panicFrames())
+		ei := g.recordExit(spec.fi, start, p, pe, nil)
+		if !spec.ignorePanic || idle {
+			g.cancelBy(pe, CancelerSubGoroutine)
+		}
+		g.applyPanicPolicy(spec, ei, &g.running)
 	} else {
-		g.cancelCause(errors.New(fi.String() + ": exit"))
+		g.recordExit(spec.fi, start, nil, nil, *err)
+		if *err != nil || !spec.optional || idle {
+			g.cancelBy(&GoExitError{FuncInfo: spec.fi, Err: *err}, CancelerSubGoroutine)
+		}
+	}
+	g.exitIfDone()
+}
+
+// exitIfDone finishes exit of g without f5 once it is canceled and no goroutine is running. see Group.exitIfDone
+func (g *MiniGroup) exitIfDone() {
+	if g.running.Load() == 0 && isContextDone(g.ctx) && g.started.Load() && !g.exitDone.Load() {
+		go g.finishExit(true) // not to delay exit of the goroutine by hooks
 	}
-	g.wg.Done()
 }
 
 // NewMiniAndGo
@@ -42,8 +42,79 @@ func TestMiniGroupGoAfterWait(t *testing.T) {
 	testGoAfterWait(t, &g)
 }
 
+func TestMiniGroupGoOptional(t *testing.T) {
+	var g MiniGroup
+	testGoOptional(t, &g)
+}
+
+func TestMiniGroupGoOptionalPanic(t *testing.T) {
+	var g MiniGroup
+	testGoOptionalPanic(t, &g)
+}
+
+func TestMiniGroupGoOptionalOnly(t *testing.T) {
+	var g MiniGroup
+	testGoOptionalOnly(t, &g)
+}
+
+func TestMiniGroupGoOptionalBeforeGo(t *testing.T) {
+	var g MiniGroup
+	testGoOptionalBeforeGo(t, &g)
+}
+
+func TestMiniGroupGoOptionalWaitIdle(t *testing.T) {
+	var g MiniGroup
+	testGoOptionalWaitIdle(t, &g)
+}
+
+func TestMiniGroupGoErr(t *testing.T) {
+	var g MiniGroup
+	testGoErr(t, &g)
+}
+
+func TestMiniGroupGoTkErr(t *testing.T) {
+	var g MiniGroup
+	testGoTkErr(t, &g)
+}
+
+func TestMiniGroupGoErrNil(t *testing.T) {
+	var g MiniGroup
+	testGoErrNil(t, &g)
+}
+
+func TestMiniGroupTypedErrors(t *testing.T) {
+	var g MiniGroup
+	testTypedErrors(t, &g)
+}
+
+func TestMiniGroupTypedExitError(t *testing.T) {
+	var g MiniGroup
+	testTypedExitError(t, &g)
+}
+
+func TestMiniGroupLimit(t *testing.T) {
+	var g MiniGroup
+	testLimit(t, &g)
+}
+
+func TestMiniGroupLimitFanOut(t *testing.T) {
+	var g MiniGroup
+	testLimitFanOut(t, &g)
+}
+
+func TestMiniGroupSetLimitRace(t *testing.T) {
+	var g MiniGroup
+	testSetLimitRace(t, &g)
+}
+
+func TestMiniGroupTryGo(t *testing.T) {
+	var g MiniGroup
+	testTryGo(t, &g)
+}
+
 func TestNewMiniAndGo(t *testing.T) {
-	ctx, _ := context.WithTimeout(context.Background(), time.Microsecond)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Microsecond)
+	defer cancel()
 
 	andGo := NewMiniAndGo(ctx, process, processOnce, processOnce, time.Second, process, processOnce, time.Second, process, processPanic)
 	andGo.Wait()
@@ -0,0 +1,123 @@
+package gogroup
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type PanicMode int
+
+const (
+	PanicRecover PanicMode = iota // default. recover the panic and cancel the group with PanicError
+	PanicRepanic                  // cancel the group, wait Grace, then panic again on the goroutine. usually crashes the process
+	PanicHandler                  // cancel the group, wait Grace, then call Handler on the goroutine
+)
+
+// PanicPolicy tells group what to do after a goroutine panics.
+// GoInfo of the panic is always recorded, and the group is canceled first unless the goroutine is
+// started by GoOptional with ignorePanic. Grace gives other goroutines a chance to exit before crash
+type PanicPolicy struct {
+	Mode PanicMode
+
+	// Grace is the max wait for other goroutines to exit. 0 means no wait
+	Grace time.Duration
+
+	// Handler is called with GoInfo of the panic in PanicHandler mode, it may call os.Exit
+	// if it returns, the goroutine exits as PanicRecover
+	Handler func(GoInfo)
+}
+
+func (g *groupBase) setPanicPolicy(p PanicPolicy) {
+	g.panicPolicy.Store(&p)
+}
+
+// policyOf returns PanicPolicy of spec, or PanicPolicy of group if spec has none
+func (g *groupBase) policyOf(spec goSpec) PanicPolicy {
+	if spec.panicPolicy != nil {
+		return *spec.panicPolicy
+	}
+	if p := g.panicPolicy.Load(); p != nil {
+		return *p
+	}
+	return PanicPolicy{}
+}
+
+// applyPanicPolicy must be called on the panicked goroutine after group is canceled.
+// running counts goroutines of group not exited, which not includes the caller
+func (g *groupBase) applyPanicPolicy(spec goSpec, ei GoInfo, running *atomic.Int32) {
+	p := g.policyOf(spec)
+	switch p.Mode {
+	case PanicRepanic:
+	case PanicHandler:
+		if p.Handler == nil {
+			return
+		}
+	default:
+		return
+	}
+	g.waitOthers(running, p.Grace)
+	if p.Mode == PanicRepanic {
+		panic(repanicError{&PanicError{
+			FuncInfo:    ei.FuncInfo,
+			Value:       ei.Panic,
+			Stack:       ei.PanicStack,
+			Frames:      ei.PanicFrames,
+			GoroutineID: curGoroutineID(),
+		}})
+	}
+	p.Handler(ei)
+}
+
+// repanicError is panicked by PanicRepanic. the stack printed by runtime is the one of panicking again,
+// so its message includes the stack of the original panic
+type repanicError struct {
+	*PanicError
+}
+
+func (e repanicError) Error() string {
+	return e.PanicError.Error() + "\n\n" + string(e.Stack)
+}
+
+func (e repanicError) Unwrap() error {
+	return e.PanicError
+}
+
+// waitOthers waits until running is 0 or timeout
+func (g *groupBase) waitOthers(running *atomic.Int32, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	for {
+		ch := g.exitChan() // before checking running, so that no exit is missed
+		if running.Load() <= 0 {
+			return
+		}
+		select {
+		case <-ch:
+		case <-t.C:
+			return
+		}
+	}
+}
+
+// exitChan returns a channel who is closed when a goroutine of group exits
+func (g *groupBase) exitChan() chan struct{} {
+	g.exitNotifyM.Lock()
+	defer g.exitNotifyM.Unlock()
+	if g.exitNotify == nil {
+		g.exitNotify = make(chan struct{})
+	}
+	return g.exitNotify
+}
+
+// notifyExit must be called after running of group decreased
+func (g *groupBase) notifyExit() {
+	g.exitNotifyM.Lock()
+	defer g.exitNotifyM.Unlock()
+	if g.exitNotify != nil {
+		close(g.exitNotify)
+		g.exitNotify = nil
+	}
+}
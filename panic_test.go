@@ -0,0 +1,126 @@
+package gogroup
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testPanicHandler(t *testing.T, g interface {
+	GoGroup
+	SetPanicPolicy(PanicPolicy)
+}) {
+	var cleaned atomic.Bool
+	handled := make(chan GoInfo, 1)
+	g.SetPanicPolicy(PanicPolicy{
+		Mode:  PanicHandler,
+		Grace: time.Second,
+		Handler: func(gi GoInfo) {
+			if !cleaned.Load() {
+				t.Error("Handler called before other goroutine exit")
+			}
+			handled <- gi
+		},
+	})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 50)
+		cleaned.Store(true)
+	})
+	g.Go(func(ctx context.Context) {
+		panic("handled")
+	})
+	g.Wait()
+	select {
+	case gi := <-handled:
+		if gi.Panic != "handled" {
+			t.Fatal("GoInfo of Handler not right", gi)
+		}
+	default:
+		t.Fatal("Handler not called")
+	}
+}
+
+func TestGroupPanicHandler(t *testing.T) {
+	var g Group
+	testPanicHandler(t, &g)
+}
+
+func TestMiniGroupPanicHandler(t *testing.T) {
+	var g MiniGroup
+	testPanicHandler(t, &g)
+}
+
+func TestPanicHandlerGrace(t *testing.T) {
+	var g Group
+	called := make(chan struct{}, 1)
+	g.GoWithPanicPolicy(func(ctx context.Context) {
+		panic("grace")
+	}, PanicPolicy{Mode: PanicHandler, Grace: time.Millisecond * 100, Handler: func(GoInfo) {
+		called <- struct{}{}
+	}})
+	g.Go(ignoreCancel)
+	start := time.Now()
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("Handler not called after Grace")
+	}
+	if time.Since(start) < time.Millisecond*80 {
+		t.Fatal("Grace not waited")
+	}
+	g.Cancel(nil)
+}
+
+func TestPanicRecoverDefault(t *testing.T) {
+	var g Group
+	g.SetPanicPolicy(PanicPolicy{Mode: PanicRepanic})
+	// per Go policy overrides group policy
+	g.GoWithPanicPolicy(func(ctx context.Context) {
+		panic("recovered")
+	}, PanicPolicy{})
+	g.Wait()
+	if g.ExitInfo().GoInfos[0].Panic != "recovered" {
+		t.Fatal("panic not recorded")
+	}
+}
+
+func TestPanicRepanic(t *testing.T) {
+	switch os.Getenv("GOGROUP_TEST_REPANIC") {
+	case "group":
+		repanic(&Group{})
+		return
+	case "mini":
+		repanic(&MiniGroup{})
+		return
+	}
+	for _, typ := range []string{"group", "mini"} {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestPanicRepanic$")
+		cmd.Env = append(os.Environ(), "GOGROUP_TEST_REPANIC="+typ)
+		out, err := cmd.CombinedOutput()
+		if err == nil || !strings.Contains(string(out), "panic(repanic) exit") {
+			t.Fatal(typ, "not repanic", err, string(out))
+		}
+		// original stack is printed even if the goroutine is unwound before panicking again
+		if !strings.Contains(string(out), "gogroup.repanic.func1()") {
+			t.Fatal(typ, "original stack not printed", string(out))
+		}
+	}
+}
+
+// repanic should crash the process
+func repanic(g interface {
+	GoGroup
+	SetPanicPolicy(PanicPolicy)
+}) {
+	g.SetPanicPolicy(PanicPolicy{Mode: PanicRepanic})
+	g.Go(func(ctx context.Context) {
+		panic("repanic")
+	})
+	g.Wait()
+	time.Sleep(time.Second)
+}
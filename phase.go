@@ -0,0 +1,151 @@
+package gogroup
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// PhaseInfo is the shutdown timing of a phase. see Group.GoWithPhase
+type PhaseInfo struct {
+	Phase      int       `json:"phase"`
+	Goroutines int       `json:"goroutines"` // number of goroutines started in the phase
+	CancelTime time.Time `json:"cancel_time"`
+	ExitTime   time.Time `json:"exit_time"`
+}
+
+func (pi PhaseInfo) String() string {
+	s := "Phase " + strconv.Itoa(pi.Phase) + ": " + strconv.Itoa(pi.Goroutines)
+	if pi.Goroutines == 1 {
+		s += " goroutine"
+	} else {
+		s += " goroutines"
+	}
+	return s + ", CancelTime: " + pi.CancelTime.Format(microsecondDate) +
+		", ExitTime: " + pi.ExitTime.Format(microsecondDate)
+}
+
+type phase struct {
+	n      int
+	ctx    context.Context
+	cancel context.CancelCauseFunc // nil for phase 0, who uses ctx of group
+
+	started  int
+	running  int
+	canceled bool
+	exited   chan struct{} // closed when all goroutines exited after canceled
+
+	cancelTime time.Time
+	exitTime   time.Time
+}
+
+// checkPhase panics if n is not a valid phase. it must be called before the goroutine acquires the limit
+func checkPhase(n int) {
+	if n < 0 {
+		panic("gogroup: negative phase " + strconv.Itoa(n))
+	}
+}
+
+// enterPhase returns the phase n, and counts a goroutine into it
+func (g *Group) enterPhase(n int) *phase {
+	g.phasesM.Lock()
+	defer g.phasesM.Unlock()
+	if g.phases == nil {
+		g.phases = make(map[int]*phase)
+	}
+	p, ok := g.phases[n]
+	if !ok {
+		p = &phase{n: n, ctx: g.ctx, exited: make(chan struct{})}
+		if n != 0 {
+			p.ctx, p.cancel = context.WithCancelCause(context.WithoutCancel(g.ctx))
+			if isContextDone(g.ctx) { // phases driver may have finished
+				p.cancel(context.Cause(g.ctx))
+			}
+		}
+		g.phases[n] = p
+	}
+	p.started++
+	p.running++
+	return p
+}
+
+func (g *Group) leavePhase(p *phase) {
+	g.phasesM.Lock()
+	p.running--
+	if p.canceled && p.running == 0 {
+		p.closeExited()
+	}
+	g.phasesM.Unlock()
+}
+
+func (p *phase) closeExited() {
+	select {
+	case <-p.exited:
+	default:
+		close(p.exited)
+	}
+}
+
+// drivePhases starts the goroutine who cancels phases one by one after g canceled
+func (g *Group) drivePhases() {
+	g.drivePhasesOnce.Do(func() {
+		g.wg.Add(1)
+		go func() {
+			defer g.wg.Done()
+			<-g.ctx.Done()
+			for p := g.nextPhase(-1); p != nil; p = g.nextPhase(p.n) {
+				g.phasesM.Lock()
+				p.canceled = true
+				p.cancelTime = time.Now()
+				if p.running == 0 {
+					p.closeExited()
+				}
+				g.phasesM.Unlock()
+				if p.cancel != nil {
+					p.cancel(context.Cause(g.ctx))
+					g.startWatchdog(p.n)
+				}
+				<-p.exited
+				g.phasesM.Lock()
+				p.exitTime = time.Now()
+				g.phasesM.Unlock()
+			}
+		}()
+	})
+}
+
+// nextPhase returns the phase after n, nil if none
+func (g *Group) nextPhase(n int) *phase {
+	g.phasesM.Lock()
+	defer g.phasesM.Unlock()
+	var next *phase
+	for i, p := range g.phases {
+		if i > n && (next == nil || i < next.n) {
+			next = p
+		}
+	}
+	return next
+}
+
+// phaseInfos returns timing of phases, nil if no goroutine started by GoWithPhase
+func (g *Group) phaseInfos() []PhaseInfo {
+	g.phasesM.Lock()
+	defer g.phasesM.Unlock()
+	if len(g.phases) == 0 || (len(g.phases) == 1 && g.phases[0] != nil) {
+		return nil
+	}
+	pis := make([]PhaseInfo, 0, len(g.phases))
+	for _, p := range g.phases {
+		pis = append(pis, PhaseInfo{
+			Phase:      p.n,
+			Goroutines: p.started,
+			CancelTime: p.cancelTime,
+			ExitTime:   p.exitTime,
+		})
+	}
+	sort.Slice(pis, func(i, j int) bool {
+		return pis[i].Phase < pis[j].Phase
+	})
+	return pis
+}
@@ -0,0 +1,105 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupGoWithPhase(t *testing.T) {
+	var g Group
+	var mu sync.Mutex
+	var order []string
+	record := func(s string) {
+		mu.Lock()
+		order = append(order, s)
+		mu.Unlock()
+	}
+	g.GoWithPhase(func(ctx context.Context) {
+		<-ctx.Done()
+		record("log shipper")
+	}, 2)
+	g.GoWithPhase(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 20) // flush
+		record("db writer")
+	}, 1)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(time.Millisecond * 20)
+		record("http server")
+	})
+	g.CancelAndWait(fmt.Errorf("stop"))
+	if strings.Join(order, ",") != "http server,db writer,log shipper" {
+		t.Fatal("order not right", order)
+	}
+	ei := g.ExitInfo()
+	if len(ei.Phases) != 3 {
+		t.Fatalf("%d phases, not 3", len(ei.Phases))
+	}
+	for i, pi := range ei.Phases {
+		if pi.Phase != i || pi.Goroutines != 1 {
+			t.Fatal("PhaseInfo not right", pi)
+		}
+		if i > 0 && pi.CancelTime.Before(ei.Phases[i-1].ExitTime) {
+			t.Fatal("phase canceled before previous phase exited", pi)
+		}
+	}
+	fmt.Println(ei)
+}
+
+func TestGroupGoWithPhaseCause(t *testing.T) {
+	var g Group
+	var cause error
+	g.GoTkWithPhase(func() {}, time.Millisecond, 1)
+	g.GoWithPhase(func(ctx context.Context) {
+		<-ctx.Done()
+		cause = context.Cause(ctx)
+	}, 3)
+	g.Go(func(ctx context.Context) {
+		time.Sleep(time.Millisecond * 10)
+	})
+	g.Wait()
+	if cause == nil || cause != g.Err() {
+		t.Fatal("cause of phase ctx not the cause of group", cause)
+	}
+	if len(g.ExitInfo().Phases) != 3 {
+		t.Fatal("phases not 3")
+	}
+}
+
+func TestGroupGoWithNegativePhase(t *testing.T) {
+	var g Group
+	defer func() {
+		if recover() == nil {
+			t.Fatal("should panic")
+		}
+	}()
+	g.GoWithPhase(func(ctx context.Context) {}, -1)
+}
+
+func TestGroupGoWithNegativePhaseLimit(t *testing.T) {
+	var g Group
+	g.SetLimit(1)
+	func() {
+		defer func() {
+			recover()
+		}()
+		g.GoTkWithPhase(func() {}, time.Millisecond, -1)
+	}()
+	if !g.TryGo(func(ctx context.Context) {}) {
+		t.Fatal("negative phase leaks the limit")
+	}
+	g.Wait()
+}
+
+func TestGroupNoPhase(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {})
+	if g.ExitInfo().Phases != nil {
+		t.Fatal("Phases should be nil")
+	}
+}
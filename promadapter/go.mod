@@ -0,0 +1,22 @@
+module github.com/xiaotushaoxia/gogroup/promadapter
+
+go 1.21
+
+require (
+	github.com/prometheus/client_golang v1.20.5
+	github.com/prometheus/client_model v0.6.1
+	github.com/prometheus/common v0.55.0
+	github.com/xiaotushaoxia/gogroup v0.0.0
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+)
+
+// gogroup has no release with Metrics yet, build against the parent directory until one is tagged
+replace github.com/xiaotushaoxia/gogroup => ../
@@ -0,0 +1,50 @@
+// Package promadapter exposes gogroup.Metrics to prometheus/client_golang.
+// it is a separate module, so the core gogroup module has no dependencies.
+//
+//	m := gogroup.NewMetrics()
+//	g.AddHooks(m.Hooks("ingest"))
+//	gatherers := prometheus.Gatherers{prometheus.DefaultGatherer, promadapter.NewGatherer(m)}
+//	http.Handle("/metrics", promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{}))
+package promadapter
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/xiaotushaoxia/gogroup"
+)
+
+// Gatherer gathers metrics of gogroup.Metrics, combine it with other gatherers by prometheus.Gatherers
+type Gatherer struct {
+	m *gogroup.Metrics
+}
+
+func NewGatherer(m *gogroup.Metrics) *Gatherer {
+	return &Gatherer{m: m}
+}
+
+// Gather implements prometheus.Gatherer
+func (g *Gatherer) Gather() ([]*dto.MetricFamily, error) {
+	var buf bytes.Buffer
+	if _, err := g.m.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	var parser expfmt.TextParser
+	mfs, err := parser.TextToMetricFamilies(&buf)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*dto.MetricFamily, 0, len(mfs))
+	for _, mf := range mfs {
+		result = append(result, mf)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetName() < result[j].GetName()
+	})
+	return result, nil
+}
+
+var _ prometheus.Gatherer = (*Gatherer)(nil)
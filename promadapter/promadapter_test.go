@@ -0,0 +1,36 @@
+package promadapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xiaotushaoxia/gogroup"
+)
+
+func TestGatherer(t *testing.T) {
+	m := gogroup.NewMetrics()
+	var g gogroup.Group
+	g.AddHooks(m.Hooks("ingest"))
+	g.Go(func(ctx context.Context) {})
+	g.Wait()
+
+	mfs, err := prometheus.Gatherers{prometheus.NewRegistry(), NewGatherer(m)}.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, mf := range mfs {
+		names[mf.GetName()] = true
+	}
+	for _, name := range []string{
+		"gogroup_goroutines_started_total",
+		"gogroup_goroutines_running",
+		"gogroup_cancellations_total",
+		"gogroup_goroutine_lifetime_seconds",
+	} {
+		if !names[name] {
+			t.Fatal("not gathered", name, names)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package gogroup
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StartupTimeoutError is the cause of Group when a goroutine started by GoReady isn't ready in time
+type StartupTimeoutError struct {
+	Name     string
+	FuncInfo FuncInfo
+	Timeout  time.Duration
+	Waiting  []string // not ready deps never started by GoReady or in a dependency cycle, empty if the goroutine itself is not ready
+}
+
+func (e *StartupTimeoutError) Error() string {
+	s := "gogroup: " + e.Name + " (" + e.FuncInfo.String() + ") not ready after " + e.Timeout.String()
+	if len(e.Waiting) > 0 {
+		s += ", waiting for " + strings.Join(e.Waiting, ", ")
+	}
+	return s
+}
+
+type readyState struct {
+	registered bool     // false if only referred as a dep
+	deps       []string // set when registered
+	fi         FuncInfo // set when registered
+	once       sync.Once
+	ch         chan struct{}
+}
+
+func (rs *readyState) ready() {
+	rs.once.Do(func() {
+		close(rs.ch)
+	})
+}
+
+func (rs *readyState) isReady() bool {
+	select {
+	case <-rs.ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// GoReady
+// start a named goroutine in Group who calls ready once it is ready to serve, such as a DB pool.
+// f starts only after all goroutines named in deps are ready, deps may be started later.
+// name must be unique in Group. see Ready and SetStartupTimeout
+func (g *Group) GoReady(name string, deps []string, f func(ctx context.Context, ready func())) {
+	g.init()
+	fi := ParserFuncInfo(f)
+	rs := g.registerReady(name, deps, fi)
+	timeout := time.Duration(g.startupTimeout.Load())
+	g.goWithSpec(func(ctx context.Context) error {
+		if timeout > 0 {
+			t := time.AfterFunc(timeout, func() {
+				if rs.isReady() {
+					return
+				}
+				se := g.blameNotReady(name)
+				se.Timeout = timeout
+				g.cancelByCanceler(se, cancelFlagCancelBySubGoroutine)
+			})
+			defer t.Stop()
+		}
+		if !g.waitReady(ctx, deps) {
+			return nil
+		}
+		f(ctx, rs.ready)
+		return nil
+	}, goSpec{fi: fi})
+}
+
+// Ready blocks until all goroutines started by GoReady (and their deps) are ready, or Group is canceled.
+// returns nil if all are ready, otherwise the cause of Group
+func (g *Group) Ready() error {
+	g.init()
+	g.readyM.Lock()
+	rss := make([]*readyState, 0, len(g.readies))
+	for _, rs := range g.readies {
+		rss = append(rss, rs)
+	}
+	g.readyM.Unlock()
+	for _, rs := range rss {
+		select {
+		case <-rs.ch:
+		case <-g.ctx.Done():
+			return context.Cause(g.ctx)
+		}
+	}
+	return nil
+}
+
+// SetStartupTimeout
+// sets the max time a goroutine started by GoReady may take to become ready, counted from GoReady.
+// if exceeded, Group is canceled with a *StartupTimeoutError naming the deepest not ready goroutine it waits for,
+// or itself if all its deps are ready. 0 means no timeout (default)
+// only affects GoReady called after it
+func (g *Group) SetStartupTimeout(d time.Duration) {
+	g.startupTimeout.Store(int64(d))
+}
+
+func (g *Group) registerReady(name string, deps []string, fi FuncInfo) *readyState {
+	g.readyM.Lock()
+	defer g.readyM.Unlock()
+	rs := g.readyStateLocked(name)
+	if rs.registered {
+		panic("gogroup: duplicate ready name " + name)
+	}
+	rs.registered = true
+	rs.deps = deps
+	rs.fi = fi
+	return rs
+}
+
+func (g *Group) readyStateLocked(name string) *readyState {
+	if g.readies == nil {
+		g.readies = make(map[string]*readyState)
+	}
+	rs, ok := g.readies[name]
+	if !ok {
+		rs = &readyState{ch: make(chan struct{})}
+		g.readies[name] = rs
+	}
+	return rs
+}
+
+// waitReady waits deps ready. return false if ctx done before that
+func (g *Group) waitReady(ctx context.Context, deps []string) bool {
+	for _, dep := range deps {
+		g.readyM.Lock()
+		rs := g.readyStateLocked(dep)
+		g.readyM.Unlock()
+		select {
+		case <-rs.ch:
+		case <-ctx.Done():
+			return false
+		}
+	}
+	return true
+}
+
+// blameNotReady follows not ready deps started by GoReady from name, and returns a StartupTimeoutError
+// naming the deepest one. it stops at a dep already visited, so a dependency cycle is named too
+func (g *Group) blameNotReady(name string) *StartupTimeoutError {
+	g.readyM.Lock()
+	defer g.readyM.Unlock()
+	visited := map[string]bool{name: true}
+	for {
+		rs := g.readyStateLocked(name)
+		var next string
+		var waiting []string
+		for _, dep := range rs.deps {
+			drs := g.readyStateLocked(dep)
+			if drs.isReady() {
+				continue
+			}
+			if drs.registered && !visited[dep] {
+				next = dep
+				break
+			}
+			waiting = append(waiting, dep)
+		}
+		if next == "" {
+			return &StartupTimeoutError{Name: name, FuncInfo: rs.fi, Waiting: waiting}
+		}
+		visited[next] = true
+		name = next
+	}
+}
@@ -0,0 +1,132 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupGoReady(t *testing.T) {
+	var g Group
+	var mu sync.Mutex
+	var order []string
+	record := func(s string) {
+		mu.Lock()
+		order = append(order, s)
+		mu.Unlock()
+	}
+	g.GoReady("consumer", []string{"db", "mq"}, func(ctx context.Context, ready func()) {
+		record("consumer")
+		ready()
+		<-ctx.Done()
+	})
+	g.GoReady("db", nil, func(ctx context.Context, ready func()) {
+		time.Sleep(time.Millisecond * 20)
+		record("db")
+		ready()
+		<-ctx.Done()
+	})
+	g.GoReady("mq", []string{"db"}, func(ctx context.Context, ready func()) {
+		record("mq")
+		ready()
+		ready() // idempotent
+		<-ctx.Done()
+	})
+	if err := g.Ready(); err != nil {
+		t.Fatal("Ready err", err)
+	}
+	g.CancelAndWait(fmt.Errorf("stop"))
+	if fmt.Sprint(order) != "[db mq consumer]" {
+		t.Fatal("order not right", order)
+	}
+}
+
+func TestGroupStartupTimeout(t *testing.T) {
+	var g Group
+	g.SetStartupTimeout(time.Millisecond * 50)
+	g.GoReady("db", nil, func(ctx context.Context, ready func()) {
+		<-ctx.Done()
+	})
+	g.GoReady("consumer", []string{"db"}, func(ctx context.Context, ready func()) {
+		t.Error("consumer should not start")
+	})
+	err := g.Ready()
+	var se *StartupTimeoutError
+	if !errors.As(err, &se) {
+		t.Fatal("err not StartupTimeoutError", err)
+	}
+	if se.Name != "db" || len(se.Waiting) != 0 {
+		t.Fatal("should be db", err)
+	}
+	if !errors.As(g.Err(), &se) {
+		t.Fatal("cause not StartupTimeoutError", g.Err())
+	}
+}
+
+func TestGroupStartupTimeoutMissingDep(t *testing.T) {
+	var g Group
+	g.SetStartupTimeout(time.Millisecond * 20)
+	g.GoReady("consumer", []string{"db"}, func(ctx context.Context, ready func()) {
+		ready()
+	})
+	var se *StartupTimeoutError
+	if !errors.As(g.Err(), &se) {
+		t.Fatal("cause not StartupTimeoutError", g.Err())
+	}
+	if se.Name != "consumer" || fmt.Sprint(se.Waiting) != "[db]" {
+		t.Fatal("StartupTimeoutError not right", se)
+	}
+}
+
+func TestGroupStartupTimeoutCycle(t *testing.T) {
+	var g Group
+	g.SetStartupTimeout(time.Millisecond * 20)
+	g.GoReady("a", []string{"b"}, func(ctx context.Context, ready func()) {
+		ready()
+	})
+	g.GoReady("b", []string{"a"}, func(ctx context.Context, ready func()) {
+		ready()
+	})
+	var se *StartupTimeoutError
+	if !errors.As(g.Err(), &se) {
+		t.Fatal("cause not StartupTimeoutError", g.Err())
+	}
+	if !(se.Name == "a" && fmt.Sprint(se.Waiting) == "[b]" || se.Name == "b" && fmt.Sprint(se.Waiting) == "[a]") {
+		t.Fatal("StartupTimeoutError not right", se)
+	}
+}
+
+func TestGroupStartupTimeoutDepWithoutTimeout(t *testing.T) {
+	var g Group
+	g.GoReady("db", nil, func(ctx context.Context, ready func()) {
+		<-ctx.Done()
+	})
+	g.SetStartupTimeout(time.Millisecond * 20)
+	g.GoReady("consumer", []string{"db"}, func(ctx context.Context, ready func()) {
+		ready()
+	})
+	var se *StartupTimeoutError
+	if !errors.As(g.Err(), &se) {
+		t.Fatal("cause not StartupTimeoutError", g.Err())
+	}
+	if se.Name != "db" || len(se.Waiting) != 0 || se.Timeout != time.Millisecond*20 {
+		t.Fatal("StartupTimeoutError not right", se)
+	}
+}
+
+func TestGroupGoReadyDuplicate(t *testing.T) {
+	var g Group
+	defer func() {
+		if recover() == nil {
+			t.Fatal("should panic")
+		}
+		g.CancelAndWait(nil)
+	}()
+	g.GoReady("db", nil, func(ctx context.Context, ready func()) {
+		<-ctx.Done()
+	})
+	g.GoReady("db", nil, func(ctx context.Context, ready func()) {})
+}
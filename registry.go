@@ -0,0 +1,151 @@
+package gogroup
+
+import "sync"
+
+// Inspectable is a group that can be registered and shown by Handler. Group and MiniGroup implement it
+type Inspectable interface {
+	Name() string
+	Snapshot() *Snapshot
+}
+
+// Option configures a group created by New or NewMini
+type Option func(*options)
+
+type options struct {
+	name string
+}
+
+// WithName sets name of the group and registers it, see Register
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+func applyOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+const defaultHistorySize = 16
+
+var registry = struct {
+	m           sync.Mutex
+	groups      []Inspectable // live groups in order of Register
+	history     []Inspectable // exited groups, the oldest first
+	historySize int
+}{historySize: defaultHistorySize}
+
+// Register adds g to the process wide registry, see Groups and Lookup.
+// Group and MiniGroup are moved to History when they exit
+func Register(g Inspectable) {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	if indexOf(registry.groups, g) >= 0 || indexOf(registry.history, g) >= 0 {
+		return
+	}
+	if e, ok := g.(interface{ exitFinished() bool }); ok && e.exitFinished() || g.Snapshot().State == GroupStateExited {
+		addHistory(g)
+		return
+	}
+	registry.groups = append(registry.groups, g)
+}
+
+// Unregister removes g from the registry, including History
+func Unregister(g Inspectable) {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	registry.groups = remove(registry.groups, g)
+	registry.history = remove(registry.history, g)
+}
+
+// Groups returns live registered groups in order of Register
+func Groups() []Inspectable {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	return append([]Inspectable(nil), registry.groups...)
+}
+
+// History returns exited registered groups, the oldest first
+func History() []Inspectable {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	return append([]Inspectable(nil), registry.history...)
+}
+
+// SetHistorySize sets the max number of exited groups kept in History, default 16. 0 means keeping none
+func SetHistorySize(n int) {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	registry.historySize = max(n, 0)
+	trimHistory()
+}
+
+// Lookup returns the registered group with name. live groups first, then the latest exited one
+func Lookup(name string) (Inspectable, bool) {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	for _, g := range registry.groups {
+		if g.Name() == name {
+			return g, true
+		}
+	}
+	for i := len(registry.history) - 1; i >= 0; i-- {
+		if registry.history[i].Name() == name {
+			return registry.history[i], true
+		}
+	}
+	return nil, false
+}
+
+// registryExited moves g to History if it is registered, called when g exits
+func registryExited(g Inspectable) {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	if indexOf(registry.groups, g) < 0 {
+		return
+	}
+	registry.groups = remove(registry.groups, g)
+	addHistory(g)
+}
+
+// registryReopened moves g back from History when Go starts a goroutine in g after it exited without f5
+func registryReopened(g Inspectable) {
+	registry.m.Lock()
+	defer registry.m.Unlock()
+	if indexOf(registry.history, g) < 0 {
+		return
+	}
+	registry.history = remove(registry.history, g)
+	registry.groups = append(registry.groups, g)
+}
+
+func addHistory(g Inspectable) {
+	registry.history = append(registry.history, g)
+	trimHistory()
+}
+
+func trimHistory() {
+	if n := len(registry.history) - registry.historySize; n > 0 {
+		registry.history = append([]Inspectable(nil), registry.history[n:]...)
+	}
+}
+
+func indexOf(gs []Inspectable, g Inspectable) int {
+	for i, v := range gs {
+		if v == g {
+			return i
+		}
+	}
+	return -1
+}
+
+func remove(gs []Inspectable, g Inspectable) []Inspectable {
+	if i := indexOf(gs, g); i >= 0 {
+		return append(gs[:i:i], gs[i+1:]...)
+	}
+	return gs
+}
@@ -0,0 +1,157 @@
+package gogroup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRegister(t *testing.T) {
+	var g1, g2 Group
+	Register(&g1)
+	Register(&g2)
+	Register(&g1)
+	if gs := Groups(); len(gs) != 2 || gs[0] != &g1 || gs[1] != &g2 {
+		t.Fatal("Groups not right", gs)
+	}
+	Unregister(&g1)
+	if gs := Groups(); len(gs) != 1 || gs[0] != &g2 {
+		t.Fatal("Unregister not right", gs)
+	}
+	Unregister(&g2)
+	Unregister(&g2)
+	if len(Groups()) != 0 {
+		t.Fatal("Groups should be empty")
+	}
+}
+
+func TestWithName(t *testing.T) {
+	g := New(context.Background(), WithName("ingest"))
+	defer Unregister(g)
+	mg := NewMini(context.Background(), WithName("mini-ingest"))
+	defer Unregister(mg)
+	if g.Name() != "ingest" || mg.Name() != "mini-ingest" {
+		t.Fatal("name not right")
+	}
+	if v, ok := Lookup("ingest"); !ok || v != g {
+		t.Fatal("Lookup not right", v)
+	}
+	if v, ok := Lookup("mini-ingest"); !ok || v != mg {
+		t.Fatal("Lookup not right", v)
+	}
+	if _, ok := Lookup("not-exist"); ok {
+		t.Fatal("Lookup should fail")
+	}
+
+	g.Go(func(ctx context.Context) {})
+	g.Wait()
+	mg.Go(func(ctx context.Context) {})
+	mg.Wait()
+	if indexOf(Groups(), g) >= 0 || indexOf(Groups(), mg) >= 0 {
+		t.Fatal("exited group should drop out of Groups")
+	}
+	if indexOf(History(), g) < 0 || indexOf(History(), mg) < 0 {
+		t.Fatal("exited group should be in History")
+	}
+	if v, ok := Lookup("ingest"); !ok || v != g {
+		t.Fatal("Lookup exited group not right", v)
+	}
+}
+
+func TestHistorySize(t *testing.T) {
+	defer SetHistorySize(defaultHistorySize)
+	SetHistorySize(2)
+	var gs [3]*Group
+	for i := range gs {
+		gs[i] = New(context.Background(), WithName("history"))
+		defer Unregister(gs[i])
+		gs[i].Go(func(ctx context.Context) {})
+		gs[i].Wait()
+	}
+	h := History()
+	if len(h) != 2 || h[0] != gs[1] || h[1] != gs[2] {
+		t.Fatal("History not right", h)
+	}
+	if v, _ := Lookup("history"); v != gs[2] {
+		t.Fatal("Lookup should return the latest", v)
+	}
+	SetHistorySize(0)
+	if len(History()) != 0 {
+		t.Fatal("History should be empty")
+	}
+}
+
+func TestRegisterExited(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {})
+	g.Wait()
+	Register(&g)
+	defer Unregister(&g)
+	if indexOf(Groups(), &g) >= 0 || indexOf(History(), &g) < 0 {
+		t.Fatal("exited group should be registered to History")
+	}
+}
+
+func testExitWithoutF5(t *testing.T, g interface {
+	Inspectable
+	GoGroup
+	AddHooks(Hooks)
+}, cancel context.CancelFunc) {
+	defer Unregister(g)
+	h := &recordHooks{}
+	g.AddHooks(h)
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	cancel()
+	for i := 0; i < 100 && indexOf(History(), g) < 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if indexOf(Groups(), g) >= 0 || indexOf(History(), g) < 0 {
+		t.Fatal("exited group should be moved to History without f5")
+	}
+	h.m.Lock()
+	n := len(h.groupEnd)
+	h.m.Unlock()
+	if n != 1 {
+		t.Fatal("OnGroupExit should be called without f5", n)
+	}
+	release := make(chan struct{})
+	g.Go(func(ctx context.Context) {
+		<-release
+	})
+	if indexOf(Groups(), g) < 0 || indexOf(History(), g) >= 0 {
+		t.Fatal("Go after exit without f5 should move group back to Groups")
+	}
+	close(release)
+	g.Wait()
+	if len(h.groupEnd) != 2 {
+		t.Fatal("OnGroupExit should be called again after the group is reopened", len(h.groupEnd))
+	}
+	if indexOf(Groups(), g) >= 0 || indexOf(History(), g) < 0 {
+		t.Fatal("exited group should be moved to History again")
+	}
+}
+
+func TestGroupExitWithoutF5(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testExitWithoutF5(t, New(ctx, WithName("exit-without-f5")), cancel)
+}
+
+func TestMiniGroupExitWithoutF5(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	testExitWithoutF5(t, NewMini(ctx, WithName("mini-exit-without-f5")), cancel)
+}
+
+func TestRegisterExitedWithoutF5(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {})
+	for i := 0; i < 100 && !g.exitFinished(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	Register(&g)
+	defer Unregister(&g)
+	if indexOf(Groups(), &g) >= 0 || indexOf(History(), &g) < 0 {
+		t.Fatal("exited group should be registered to History")
+	}
+}
@@ -0,0 +1,74 @@
+package gogroup
+
+import (
+	"time"
+)
+
+type RestartMode int
+
+const (
+	RestartNever   RestartMode = iota // default. exit of goroutine cancels the Group
+	RestartOnPanic                    // restart goroutine when it panics
+	RestartAlways                     // restart goroutine whenever it exits
+)
+
+// RestartPolicy tells Group whether and how to restart a goroutine after it exits.
+// Group will be canceled only when the goroutine exits and won't be restarted anymore (restart budget runs out)
+// no restart will happen after Group is canceled
+type RestartPolicy struct {
+	Mode RestartMode
+
+	// MaxRestarts is the max number of restarts in Window. <= 0 means never restart
+	MaxRestarts int
+
+	// Window is the sliding window of MaxRestarts. 0 means the whole life of Group
+	Window time.Duration
+
+	// Backoff is the wait before the first restart
+	// if MaxBackoff > Backoff, the wait doubles after each restart, up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+type restarter struct {
+	policy   RestartPolicy
+	restarts []time.Time // restart times in Window
+	backoff  time.Duration
+}
+
+// next reports whether the goroutine should be restarted after gi, and how long to wait before restart
+func (r *restarter) next(gi GoInfo) (time.Duration, bool) {
+	switch r.policy.Mode {
+	case RestartAlways:
+	case RestartOnPanic:
+		if gi.Panic == nil {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if r.policy.MaxRestarts <= 0 {
+		return 0, false
+	}
+	if r.policy.Window > 0 {
+		i := 0
+		for i < len(r.restarts) && gi.ExitTime.Sub(r.restarts[i]) > r.policy.Window {
+			i++
+		}
+		r.restarts = r.restarts[i:]
+	}
+	if len(r.restarts) >= r.policy.MaxRestarts {
+		return 0, false
+	}
+	r.restarts = append(r.restarts, gi.ExitTime)
+
+	if r.backoff == 0 {
+		r.backoff = r.policy.Backoff
+	} else if r.backoff < r.policy.MaxBackoff {
+		r.backoff *= 2
+		if r.backoff > r.policy.MaxBackoff {
+			r.backoff = r.policy.MaxBackoff
+		}
+	}
+	return r.backoff, true
+}
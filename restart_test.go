@@ -0,0 +1,112 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupGoWithRestart(t *testing.T) {
+	var g Group
+	var n atomic.Int32
+	g.GoWithRestart(func(ctx context.Context) {
+		if n.Add(1) < 3 {
+			panic("flaky")
+		}
+		<-ctx.Done()
+	}, RestartPolicy{Mode: RestartOnPanic, MaxRestarts: 5, Backoff: time.Millisecond})
+	g.Go(func(ctx context.Context) {
+		time.Sleep(time.Millisecond * 100)
+	})
+	ei := g.ExitInfo()
+	if n.Load() != 3 {
+		t.Fatalf("run %d times, not 3", n.Load())
+	}
+	if !ei.CancelBySubGoroutine {
+		t.Fatal("not CancelBySubGoroutine")
+	}
+	attempts := map[int]bool{}
+	for _, gi := range ei.GoInfos {
+		attempts[gi.Attempt] = true
+	}
+	if len(ei.GoInfos) != 4 || !attempts[1] || !attempts[2] || !attempts[3] {
+		t.Fatal("GoInfos not right\n", ei)
+	}
+}
+
+func TestGroupGoWithRestartBudget(t *testing.T) {
+	var g Group
+	var n atomic.Int32
+	g.GoWithRestart(func(ctx context.Context) {
+		n.Add(1)
+	}, RestartPolicy{Mode: RestartAlways, MaxRestarts: 2, Window: time.Minute})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	ei := g.ExitInfo()
+	if n.Load() != 3 {
+		t.Fatalf("run %d times, not 3", n.Load())
+	}
+	if len(ei.GoInfos) != 4 {
+		t.Fatal("GoInfos not 4\n", ei)
+	}
+	fmt.Println(ei)
+}
+
+func TestGroupGoWithRestartOnPanicNormalExit(t *testing.T) {
+	var g Group
+	var n atomic.Int32
+	g.GoWithRestart(func(ctx context.Context) {
+		n.Add(1)
+	}, RestartPolicy{Mode: RestartOnPanic, MaxRestarts: 2})
+	g.Wait()
+	if n.Load() != 1 {
+		t.Fatalf("run %d times, not 1", n.Load())
+	}
+}
+
+func TestGroupGoTkWithRestartCancel(t *testing.T) {
+	var g Group
+	g.GoTkWithRestart(func() {
+		panic("tk")
+	}, time.Millisecond, RestartPolicy{Mode: RestartOnPanic, MaxRestarts: 100, Backoff: time.Hour})
+	time.Sleep(time.Millisecond * 50)
+	g.CancelAndWait(fmt.Errorf("stop"))
+	ei := g.ExitInfo()
+	if !ei.CancelByUser || len(ei.GoInfos) != 1 {
+		t.Fatal("should be canceled by user during backoff\n", ei)
+	}
+}
+
+func Test_restarterBackoff(t *testing.T) {
+	r := restarter{policy: RestartPolicy{Mode: RestartAlways, MaxRestarts: 10, Backoff: time.Second, MaxBackoff: 5 * time.Second}}
+	var got []time.Duration
+	for i := 0; i < 5; i++ {
+		d, ok := r.next(GoInfo{ExitTime: time.Now()})
+		if !ok {
+			t.Fatal("should restart")
+		}
+		got = append(got, d)
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("backoff %d: %v not %v", i, got[i], want[i])
+		}
+	}
+}
+
+func Test_restarterWindow(t *testing.T) {
+	r := restarter{policy: RestartPolicy{Mode: RestartAlways, MaxRestarts: 2, Window: time.Second}}
+	now := time.Now()
+	for i, c := range []struct {
+		at time.Duration
+		ok bool
+	}{{0, true}, {100 * time.Millisecond, true}, {200 * time.Millisecond, false}, {1500 * time.Millisecond, true}} {
+		if _, ok := r.next(GoInfo{ExitTime: now.Add(c.at)}); ok != c.ok {
+			t.Fatalf("%d: restart %v not %v", i, ok, c.ok)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrRunnerRunning = errors.New("gogroup: runner is running")
+
+// Runner holds a recipe of goroutines, and runs them in a fresh Group each time.
+// unlike Group, it can run, stop and run again, e.g. restart all workers when config reloads.
+// zero value is ready to use
+type Runner struct {
+	mu         sync.Mutex
+	recipe     []func(*Group)
+	current    *Group
+	done       chan struct{} // closed when current run exited and recorded
+	history    []*ExitInfo
+	maxHistory int
+}
+
+// Go adds f to the recipe. takes effect from the next Run
+func (r *Runner) Go(f func(context.Context)) {
+	r.GoWithFuncInfo(f, ParserFuncInfo(f))
+}
+
+// GoTk adds f to the recipe. takes effect from the next Run
+func (r *Runner) GoTk(f func(), d time.Duration) {
+	r.GoTkWithFuncInfo(f, d, ParserFuncInfo(f))
+}
+
+func (r *Runner) GoWithFuncInfo(f func(context.Context), fi FuncInfo) {
+	r.add(func(g *Group) {
+		g.GoWithFuncInfo(f, fi)
+	})
+}
+
+func (r *Runner) GoTkWithFuncInfo(f func(), d time.Duration, fi FuncInfo) {
+	r.add(func(g *Group) {
+		g.GoTkWithFuncInfo(f, d, fi)
+	})
+}
+
+// SetMaxHistory sets how many ExitInfo of past runs are kept. <= 0 means keep all (default)
+func (r *Runner) SetMaxHistory(n int) {
+	r.mu.Lock()
+	r.maxHistory = n
+	r.trimHistory()
+	r.mu.Unlock()
+}
+
+// Run starts all goroutines of the recipe in a fresh Group with root ctx, and returns the Group.
+// returns ErrRunnerRunning if the Group of last Run hasn't exited
+func (r *Runner) Run(ctx context.Context) (*Group, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current != nil {
+		return nil, ErrRunnerRunning
+	}
+	g := New(ctx)
+	for _, f := range r.recipe {
+		f(g)
+	}
+	done := make(chan struct{})
+	r.current, r.done = g, done
+	go func() {
+		ei := g.ExitInfo()
+		r.mu.Lock()
+		r.history = append(r.history, ei)
+		r.trimHistory()
+		r.current = nil
+		r.mu.Unlock()
+		close(done)
+	}()
+	return g, nil
+}
+
+// Stop cancels the Group of last Run with err, waits it exit and returns its ExitInfo.
+// returns nil if not running
+func (r *Runner) Stop(err error) *ExitInfo {
+	r.mu.Lock()
+	g, done := r.current, r.done
+	r.mu.Unlock()
+	if g == nil {
+		return nil
+	}
+	g.Cancel(err)
+	<-done
+	return g.ExitInfo()
+}
+
+// Restart same as Stop(err) and then Run(ctx)
+func (r *Runner) Restart(ctx context.Context, err error) (*Group, error) {
+	r.Stop(err)
+	return r.Run(ctx)
+}
+
+// Running returns the Group of last Run, nil if it has exited
+func (r *Runner) Running() *Group {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// History returns ExitInfo of past runs, the oldest first
+func (r *Runner) History() []*ExitInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*ExitInfo(nil), r.history...)
+}
+
+func (r *Runner) add(f func(*Group)) {
+	r.mu.Lock()
+	r.recipe = append(r.recipe, f)
+	r.mu.Unlock()
+}
+
+func (r *Runner) trimHistory() {
+	if r.maxHistory > 0 && len(r.history) > r.maxHistory {
+		r.history = append([]*ExitInfo(nil), r.history[len(r.history)-r.maxHistory:]...)
+	}
+}
@@ -0,0 +1,67 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunner(t *testing.T) {
+	var r Runner
+	var n atomic.Int32
+	r.Go(func(ctx context.Context) {
+		n.Add(1)
+		<-ctx.Done()
+	})
+	r.GoTk(func() {}, time.Millisecond)
+	for i := 0; i < 3; i++ {
+		g, err := r.Run(context.Background())
+		if err != nil {
+			t.Fatal("Run err", err)
+		}
+		if _, err = r.Run(context.Background()); !errors.Is(err, ErrRunnerRunning) {
+			t.Fatal("should be running", err)
+		}
+		if r.Running() != g {
+			t.Fatal("Running not g")
+		}
+		ei := r.Stop(fmt.Errorf("reload %d", i))
+		if ei.Cause.Error() != fmt.Sprintf("reload %d", i) || len(ei.GoInfos) != 2 {
+			t.Fatal("ExitInfo not right", ei)
+		}
+	}
+	if n.Load() != 3 {
+		t.Fatalf("run %d times, not 3", n.Load())
+	}
+	if len(r.History()) != 3 || r.Running() != nil {
+		t.Fatal("History not 3")
+	}
+	if r.Stop(nil) != nil {
+		t.Fatal("Stop should return nil if not running")
+	}
+}
+
+func TestRunnerExitBySelf(t *testing.T) {
+	var r Runner
+	r.SetMaxHistory(2)
+	r.Go(func(ctx context.Context) {})
+	for i := 0; i < 3; i++ {
+		g, err := r.Run(context.Background())
+		if err != nil {
+			t.Fatal("Run err", err)
+		}
+		g.Wait()
+		for r.Running() != nil { // history is recorded just after exit
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if len(r.History()) != 2 {
+		t.Fatal("History not 2")
+	}
+	if _, err := r.Restart(context.Background(), nil); err != nil {
+		t.Fatal("Restart err", err)
+	}
+}
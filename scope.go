@@ -8,20 +8,26 @@ import (
 type ResultErrorFunc[T any] func(context.Context) (T, error)
 type ErrorFunc func(context.Context) error
 
+// AllSuccessWithResult calls fs concurrently and returns all results if all of them succeed.
+// otherwise returns the first error. if one of fs panics, the error is a *PanicError
 func AllSuccessWithResult[T any](ctx context.Context, fs ...ResultErrorFunc[T]) (ts []T, err error) {
 	return allSuccessWithResult(ctx, fs, fs2fis(fs))
 }
 
+// FirstSuccessWithResult calls fs concurrently and returns the first successful result.
+// if all of them fail, returns the joined errors. if one of fs panics, the error is a *PanicError
 func FirstSuccessWithResult[T any](ctx context.Context, fs ...ResultErrorFunc[T]) (t T, err error) {
 	return firstSuccessWithResult(ctx, fs, fs2fis(fs))
 }
 
+// AllSuccess same as AllSuccessWithResult, but fs return no result
 func AllSuccess(ctx context.Context, fs ...ErrorFunc) error {
 	fs2, fis := convertErrorFunc2ResultErrorFunc(fs...)
 	_, err := allSuccessWithResult(ctx, fs2, fis)
 	return err
 }
 
+// FirstSuccess same as FirstSuccessWithResult, but fs return no result
 func FirstSuccess(ctx context.Context, fs ...ErrorFunc) error {
 	fs2, fis := convertErrorFunc2ResultErrorFunc(fs...)
 	_, err := firstSuccessWithResult(ctx, fs2, fis)
@@ -2,6 +2,7 @@ package gogroup
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"testing"
@@ -282,3 +283,35 @@ func Test_nil(t *testing.T) {
 	t2, ok := a.Load().(time.Time)
 	fmt.Println(t2, ok)
 }
+
+func TestAllSuccessPanic(t *testing.T) {
+	err := AllSuccess(context.Background(),
+		func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		func(ctx context.Context) error {
+			panic("boom")
+		},
+	)
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatal("err not PanicError", err)
+	}
+	if pe.Value != "boom" {
+		t.Fatal("panic value not boom", pe.Value)
+	}
+}
+
+func TestFirstSuccessWithResultPanic(t *testing.T) {
+	_, err := FirstSuccessWithResult(context.Background(), ff, func(ctx context.Context) (int, error) {
+		panic(errSentinel)
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatal("err not PanicError", err)
+	}
+	if !errors.Is(err, errSentinel) {
+		t.Fatal("errors.Is not reach panic value", err)
+	}
+}
@@ -0,0 +1,87 @@
+package gogroup
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Straggler is a goroutine who hasn't exited in time after its group was canceled
+type Straggler struct {
+	FuncInfo    FuncInfo
+	GoroutineID int64
+	StartTime   time.Time
+	Stack       []byte // current stack of the goroutine, nil if not found
+}
+
+// ShutdownTimeoutError is returned by CancelAndWaitTimeout when some goroutines haven't exited in time
+type ShutdownTimeoutError struct {
+	Timeout    time.Duration
+	Stragglers []Straggler
+}
+
+func (e *ShutdownTimeoutError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("gogroup: ")
+	sb.WriteString(strconv.Itoa(len(e.Stragglers)))
+	if len(e.Stragglers) == 1 {
+		sb.WriteString(" goroutine not exited after ")
+	} else {
+		sb.WriteString(" goroutines not exited after ")
+	}
+	sb.WriteString(e.Timeout.String())
+	for i, s := range e.Stragglers {
+		if i == 0 {
+			sb.WriteString(": ")
+		} else {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(s.FuncInfo.String())
+	}
+	return sb.String()
+}
+
+// CancelAndWaitTimeout same as CancelAndWait, but waits at most timeout.
+// if some goroutines haven't exited after timeout, returns a *ShutdownTimeoutError with their FuncInfo and current stacks.
+// Group is not exited in that case, it is up to the caller to wait again or os.Exit.
+// Stragglers is empty if all goroutines exited but Group is still exiting, such as a slow OnGroupExit hook
+func (g *Group) CancelAndWaitTimeout(err error, timeout time.Duration) error {
+	g.init()
+	g.cancelByCanceler(err, cancelFlagCancelByUser)
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-g.watch().Done():
+		return nil
+	case <-t.C:
+	}
+	ss := g.stragglers(-1)
+	select {
+	case <-g.watch().Done(): // exited just now
+		return nil
+	default:
+	}
+	return &ShutdownTimeoutError{Timeout: timeout, Stragglers: ss}
+}
+
+// stragglers returns running goroutines of phase n with their current stacks, a negative n means all phases
+func (g *Group) stragglers(n int) []Straggler {
+	lgs := g.liveGos()
+	if len(lgs) == 0 {
+		return nil
+	}
+	stacks := goroutineStacks()
+	ss := make([]Straggler, 0, len(lgs))
+	for _, lg := range lgs {
+		if n >= 0 && lg.phase != n {
+			continue
+		}
+		ss = append(ss, Straggler{
+			FuncInfo:    lg.fi,
+			GoroutineID: lg.goroutineID,
+			StartTime:   lg.startTime,
+			Stack:       stacks[lg.goroutineID],
+		})
+	}
+	return ss
+}
@@ -0,0 +1,82 @@
+package gogroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ignoreCancel(ctx context.Context) {
+	time.Sleep(time.Millisecond * 300)
+}
+
+func TestGroupCancelAndWaitTimeout(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.Go(ignoreCancel)
+	err := g.CancelAndWaitTimeout(fmt.Errorf("stop"), time.Millisecond*50)
+	var se *ShutdownTimeoutError
+	if !errors.As(err, &se) {
+		t.Fatal("err not ShutdownTimeoutError", err)
+	}
+	if len(se.Stragglers) != 1 {
+		t.Fatalf("%d stragglers, not 1", len(se.Stragglers))
+	}
+	s := se.Stragglers[0]
+	if !strings.HasSuffix(s.FuncInfo.FuncName, ".ignoreCancel") {
+		t.Fatal("straggler not ignoreCancel", s.FuncInfo)
+	}
+	if !strings.Contains(string(s.Stack), "ignoreCancel") {
+		t.Fatal("stack not contain ignoreCancel", string(s.Stack))
+	}
+	if !strings.Contains(err.Error(), "1 goroutine not exited after 50ms") {
+		t.Fatal("Error not right", err)
+	}
+	if err = g.CancelAndWaitTimeout(nil, time.Second); err != nil {
+		t.Fatal("should exit in time", err)
+	}
+	if g.Err().Error() != "stop" {
+		t.Fatal("err not stop", g.Err())
+	}
+}
+
+func TestGroupCancelAndWaitTimeoutInTime(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	if err := g.CancelAndWaitTimeout(fmt.Errorf("stop"), time.Second); err != nil {
+		t.Fatal("should exit in time", err)
+	}
+}
+
+type slowExitHooks struct {
+	NopHooks
+}
+
+func (slowExitHooks) OnGroupExit(*ExitInfo) {
+	time.Sleep(time.Millisecond * 300)
+}
+
+func TestGroupCancelAndWaitTimeoutNoStraggler(t *testing.T) {
+	var g Group
+	g.AddHooks(slowExitHooks{})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	start := time.Now()
+	err := g.CancelAndWaitTimeout(fmt.Errorf("stop"), time.Millisecond*50)
+	if d := time.Since(start); d > time.Millisecond*200 {
+		t.Fatal("CancelAndWaitTimeout blocks after timeout", d)
+	}
+	var se *ShutdownTimeoutError
+	if !errors.As(err, &se) || len(se.Stragglers) != 0 {
+		t.Fatal("err not ShutdownTimeoutError without stragglers", err)
+	}
+	g.Wait()
+}
@@ -0,0 +1,163 @@
+package gogroup
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type GroupState int32
+
+const (
+	GroupStateInit    = GroupState(groupStateInit)    // no goroutine started yet
+	GroupStateRunning = GroupState(groupStateRunning) // goroutines started, maybe all exited if nobody waits
+	GroupStateExited  = GroupState(groupStateExited)  // exited and waited, Go will panic
+)
+
+func (s GroupState) String() string {
+	switch s {
+	case GroupStateInit:
+		return "Init"
+	case GroupStateRunning:
+		return "Running"
+	case GroupStateExited:
+		return "Exited"
+	}
+	return "GroupState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Canceler is who canceled the group
+type Canceler int32
+
+const (
+	CancelerNone         = Canceler(cancelFlagInit) // not canceled yet
+	CancelerUser         = Canceler(cancelFlagCancelByUser)
+	CancelerRootContext  = Canceler(cancelFlagCancelByRootContext)
+	CancelerSubGoroutine = Canceler(cancelFlagCancelBySubGoroutine)
+)
+
+func (c Canceler) String() string {
+	switch c {
+	case CancelerNone:
+		return "None"
+	case CancelerUser:
+		return "CancelByUser"
+	case CancelerRootContext:
+		return "CancelByRootContext"
+	case CancelerSubGoroutine:
+		return "CancelBySubGoroutine"
+	}
+	return "Canceler(" + strconv.Itoa(int(c)) + ")"
+}
+
+// RunningInfo is a goroutine still running in Group
+type RunningInfo struct {
+	FuncInfo    FuncInfo
+	GoroutineID int64
+	StartTime   time.Time // start time of current attempt
+	Uptime      time.Duration
+	Attempt     int
+	Interval    time.Duration // interval of GoTk, 0 for Go
+}
+
+// Snapshot is the state of Group at Time. see Group.Snapshot
+type Snapshot struct {
+	Time         time.Time
+	State        GroupState
+	Canceler     Canceler
+	Cause        error      // nil if not canceled
+	CancelTime   *time.Time // nil if not canceled
+	FirstUseLine string
+	FirstUseTime time.Time
+	Running      []RunningInfo // sorted by StartTime
+	Exited       []GoInfo
+}
+
+func (s Snapshot) String() string {
+	var builder strings.Builder
+	builder.WriteString("====Group Snapshot====\n")
+	builder.WriteString("Time: " + s.Time.Format(microsecondDate))
+	builder.WriteString(", State: " + s.State.String())
+	if s.CancelTime != nil {
+		builder.WriteString(", " + s.Canceler.String())
+		builder.WriteString(" at " + s.CancelTime.Format(microsecondDate))
+	}
+	builder.WriteByte('\n')
+	if s.Cause != nil {
+		builder.WriteString("Cause: " + s.Cause.Error() + "\n")
+	}
+	builder.WriteString(strconv.Itoa(len(s.Running)) + " Running, " + strconv.Itoa(len(s.Exited)) + " Exited\n")
+	for _, ri := range s.Running {
+		builder.WriteString("Running: ")
+		builder.WriteString(ri.FuncInfo.String())
+		builder.WriteString(", goroutine " + strconv.FormatInt(ri.GoroutineID, 10))
+		builder.WriteString(", Uptime: " + ri.Uptime.String())
+		if ri.Attempt > 1 {
+			builder.WriteString(", Attempt: " + strconv.Itoa(ri.Attempt))
+		}
+		if ri.Interval > 0 {
+			builder.WriteString(", Interval: " + ri.Interval.String())
+		}
+		builder.WriteByte('\n')
+	}
+	for _, gi := range s.Exited {
+		builder.WriteString("Exited: ")
+		builder.WriteString(gi.FuncInfo.String())
+		builder.WriteString(", ExitTime: " + gi.ExitTime.Format(microsecondDate))
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// Snapshot returns the current state of MiniGroup without blocking.
+// MiniGroup doesn't keep goroutines, so Running and Exited are always empty
+func (g *MiniGroup) Snapshot() *Snapshot {
+	g.init()
+	s := &Snapshot{Time: time.Now(), Canceler: Canceler(g.canceler.Load())}
+	switch {
+	case g.exited.Load():
+		s.State = GroupStateExited
+	case g.started.Load():
+		s.State = GroupStateRunning
+	}
+	if isContextDone(g.ctx) {
+		s.Cause, s.CancelTime = context.Cause(g.ctx), g.cancelAt.Load()
+		if s.Canceler == CancelerNone {
+			s.Canceler = CancelerRootContext // MiniGroup doesn't watch root ctx
+		}
+	}
+	return s
+}
+
+// Snapshot returns the current state of Group without blocking.
+// unlike f5, it can be called at any time and won't stop Go
+func (g *Group) Snapshot() *Snapshot {
+	g.init()
+	now := time.Now()
+	s := &Snapshot{
+		Time:         now,
+		State:        GroupState(g.state.Load()),
+		Canceler:     Canceler(g.cancelFlag.Load()),
+		FirstUseLine: g.firstUseLine,
+		FirstUseTime: g.firstUseTime,
+	}
+	if ct, ok := g.cancelAt.Load().(time.Time); ok {
+		s.CancelTime = &ct
+		s.Cause = context.Cause(g.ctx)
+	}
+	for _, lg := range g.liveGos() {
+		s.Running = append(s.Running, RunningInfo{
+			FuncInfo:    lg.fi,
+			GoroutineID: lg.goroutineID,
+			StartTime:   lg.startTime,
+			Uptime:      now.Sub(lg.startTime),
+			Attempt:     lg.attempt,
+			Interval:    lg.interval,
+		})
+	}
+	g.exitsM.Lock()
+	s.Exited = append(make([]GoInfo, 0, len(g.exits)), g.exits...)
+	g.exitsM.Unlock()
+	return s
+}
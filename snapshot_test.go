@@ -0,0 +1,53 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupSnapshot(t *testing.T) {
+	var g Group
+	if s := g.Snapshot(); s.State != GroupStateInit || len(s.Running) != 0 {
+		t.Fatal("snapshot of new group not right", s)
+	}
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.GoOptional(func(ctx context.Context) {}, false)
+	time.Sleep(time.Millisecond * 20)
+	s := g.Snapshot()
+	if s.State != GroupStateRunning || s.Canceler != CancelerNone || s.Cause != nil {
+		t.Fatal("snapshot of running group not right", s)
+	}
+	if len(s.Running) != 1 || len(s.Exited) != 1 || !s.Exited[0].Optional {
+		t.Fatal("snapshot goroutines not right", s)
+	}
+	if s.Running[0].Uptime < time.Millisecond*20 || s.Running[0].GoroutineID == 0 {
+		t.Fatal("RunningInfo not right", s.Running[0])
+	}
+	if !strings.Contains(s.String(), "1 Running, 1 Exited") {
+		t.Fatal("String not right", s)
+	}
+	fmt.Println(s)
+
+	g.CancelAndWait(fmt.Errorf("stop"))
+	s = g.Snapshot()
+	if s.State != GroupStateExited || s.Canceler != CancelerUser || s.Cause.Error() != "stop" || s.CancelTime == nil {
+		t.Fatal("snapshot of exited group not right", s)
+	}
+	if len(s.Running) != 0 || len(s.Exited) != 2 {
+		t.Fatal("snapshot goroutines not right", s)
+	}
+}
+
+func TestCancelerString(t *testing.T) {
+	if CancelerRootContext.String() != "CancelByRootContext" || Canceler(9).String() != "Canceler(9)" {
+		t.Fatal("String not right")
+	}
+	if GroupStateExited.String() != "Exited" || GroupState(9).String() != "GroupState(9)" {
+		t.Fatal("String not right")
+	}
+}
@@ -0,0 +1,79 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+	"runtime/trace"
+)
+
+// groupTrace is the runtime/trace task of Group, see Group.SetTrace
+type groupTrace struct {
+	ctx  context.Context
+	task *trace.Task
+}
+
+// traceKey marks ctx of goroutine is in a trace task, so ticks of GoTk are traced as regions
+type traceKey struct{}
+
+// SetTrace enables runtime/trace annotations of Group, should be called before Go.
+// Group is a task "gogroup.group", each goroutine is a task "gogroup.go" named after its FuncInfo
+// and each tick of GoTk is a region "gogroup.tick". cancel is logged to the group task.
+// annotations cost nothing until tracing starts, see `go tool trace`
+func (g *Group) SetTrace(on bool) {
+	g.traceOn.Store(on)
+}
+
+func (g *Group) startTrace() {
+	if !g.traceOn.Load() {
+		return
+	}
+	name := "gogroup.group"
+	if n := g.getName(); n != "" {
+		name += " " + n
+	}
+	ctx, task := trace.NewTask(context.Background(), name)
+	trace.Log(ctx, "gogroup.first_use", g.firstUseLine)
+	g.trace.Store(&groupTrace{ctx: ctx, task: task})
+}
+
+func (g *Group) traceCancel(err error, canceler Canceler) {
+	if gt := g.trace.Load(); gt != nil {
+		trace.Log(gt.ctx, "gogroup.cancel", canceler.String()+": "+fmt.Sprint(err))
+	}
+}
+
+func (g *Group) endTrace() {
+	if gt := g.trace.Load(); gt != nil {
+		gt.task.End()
+	}
+}
+
+// traceGo wraps f in a task of fi if trace is enabled
+func (g *Group) traceGo(fi FuncInfo, f func(context.Context) error) func(context.Context) error {
+	if g.trace.Load() == nil {
+		return f
+	}
+	return func(ctx context.Context) error {
+		ctx, task := trace.NewTask(context.WithValue(ctx, traceKey{}, true), "gogroup.go "+fi.FuncName)
+		defer task.End()
+		if fi.Description != "" {
+			trace.Log(ctx, "gogroup.desc", fi.Description)
+		}
+		err := f(ctx)
+		if err != nil {
+			trace.Log(ctx, "gogroup.err", err.Error())
+		}
+		return err
+	}
+}
+
+// traceTick calls f in a region if ctx is traced, see Group.SetTrace
+func traceTick(ctx context.Context, f func() error) (err error) {
+	if ctx.Value(traceKey{}) == nil {
+		return f()
+	}
+	trace.WithRegion(ctx, "gogroup.tick", func() {
+		err = f()
+	})
+	return
+}
@@ -0,0 +1,60 @@
+package gogroup
+
+import (
+	"bytes"
+	"context"
+	"runtime/trace"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGroupSetTrace(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skip("trace is already started", err)
+	}
+	var g Group
+	g.SetName("traced")
+	g.SetTrace(true)
+	var ticked bool
+	g.GoWithFuncInfo(func(ctx context.Context) {
+		if !trace.IsEnabled() || ctx.Value(traceKey{}) == nil {
+			t.Error("ctx not traced")
+		}
+		<-ctx.Done()
+	}, FuncInfo{FuncName: "traced loop"})
+	g.GoTk(func() {
+		if ticked {
+			panic("tick panic")
+		}
+		ticked = true
+	}, time.Millisecond*10)
+	g.Wait()
+	trace.Stop()
+
+	pe := g.ExitInfo().GoInfos[0]
+	if pe.Panic == nil {
+		pe = g.ExitInfo().GoInfos[1]
+	}
+	for _, fr := range pe.PanicFrames {
+		if strings.HasPrefix(fr.Function, "runtime/trace.") {
+			t.Fatal("trace frame not filtered", fr)
+		}
+	}
+	for _, s := range []string{"gogroup.group traced", "gogroup.go traced loop", "gogroup.tick", "gogroup.cancel"} {
+		if !bytes.Contains(buf.Bytes(), []byte(s)) {
+			t.Fatal("trace not contain", s)
+		}
+	}
+}
+
+func TestGroupNoTrace(t *testing.T) {
+	var g Group
+	g.Go(func(ctx context.Context) {
+		if ctx.Value(traceKey{}) != nil {
+			t.Error("ctx should not be traced")
+		}
+	})
+	g.Wait()
+}
@@ -4,44 +4,14 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"runtime"
-	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// stack returns a formatted stack trace of the goroutine that calls it, skipping the most recent 'skip' calls
-//
-// skip	example:
-//
-//		stack: goroutine 50 [running]:
-//	1	runtime/debug.Stack()
-//	1	        xxxxxxxxxxx/src/runtime/debug/stack.go:24 +0x5e
-//	2	main.Stack(0xc000cfdd60?)
-//	2	        xxxxxxxxxxx/utils.go:499 +0x25
-//	3	main.(*MiniGroup).go1.func1.1()
-//	3	        xxxxxxxxxxx/utils.go:454 +0x65
-//	4	panic({0xa6f100?, 0xf8d898?})
-//	4	       xxxxxxxxxxx//src/runtime/panic.go:914 +0x21f
-//	5	main.main.func3()
-//	5	       xxxxxxxxxxx/main.go:71 +0x45
-//	6	main.(*MiniGroup).GoTk.toTkFunc.func1()
-//	6	        xxxxxxxxxxx/utils.go:491 +0x87
-//	7	main.(*MiniGroup).go1.func1()
-//	7	        xxxxxxxxxxx/utils.go:459 +0x6c
-//	8	created by main.(*MiniGroup).go1 in goroutine 1
-//	8	        xxxxxxxxxxx/utils.go:451 +0xc5
-func stack(n int) []byte {
-	lines := bytes2lines(debug.Stack())
-	if n == 0 || len(lines) <= n*2+1 { // if n is too large. not skip
-		return bytes.Join(lines, []byte{})
-	}
-	lines = append([][]byte{lines[0]}, lines[2*n+1:]...)
-	return bytes.Join(lines, []byte{})
-}
-
 func bytes2lines(bs []byte) [][]byte {
 	reader := bufio.NewReader(bytes.NewReader(bs))
 	var lines = make([][]byte, 0)
@@ -70,26 +40,46 @@ func getCallerLine(skip int) string {
 }
 
 func toTkFunc(f func(), d time.Duration) func(context.Context) {
+	tkf := toTkErrFunc(func() error {
+		f()
+		return nil
+	}, d)
 	return func(ctx context.Context) {
+		_ = tkf(ctx)
+	}
+}
+
+// toTkErrFunc returns a func who exec f every d until ctx done or f returns a non-nil error
+func toTkErrFunc(f func() error, d time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
 		tk := time.NewTicker(d)
 		defer tk.Stop()
 		done := ctx.Done()
 		for {
 			select {
 			case <-done:
-				return
+				return nil
 			default: // if f cost longer than d, may not exit forever
 				select {
 				case <-done:
-					return
+					return nil
 				case <-tk.C:
-					f()
+					if err := tick(ctx, f); err != nil {
+						return err
+					}
 				}
 			}
 		}
 	}
 }
 
+func toErrFunc(f func(context.Context)) func(context.Context) error {
+	return func(ctx context.Context) error {
+		f(ctx)
+		return nil
+	}
+}
+
 func parserGoroutineInStack(bs []byte) string {
 	flag := "goroutine "
 	indexAny := bytes.Index(bs, []byte(flag))
@@ -104,18 +94,66 @@ func parserGoroutineInStack(bs []byte) string {
 	return flag + string(bs2[:indexByte])
 }
 
-func getGoExitInfo(fi FuncInfo, panicValue any) (GoInfo, error) {
-	var tail string
+// curGoroutineID returns id of the calling goroutine
+func curGoroutineID() int64 {
+	buf := make([]byte, 64)
+	return parserGoroutineID(buf[:runtime.Stack(buf, false)])
+}
+
+// goroutineStacks returns stacks of all goroutines, keyed by goroutine id
+func goroutineStacks() map[int64][]byte {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, len(buf)*2)
+	}
+	stacks := make(map[int64][]byte)
+	for _, st := range bytes.Split(buf, []byte("\n\n")) {
+		if id := parserGoroutineID(st); id != 0 {
+			stacks[id] = st
+		}
+	}
+	return stacks
+}
+
+// parserGoroutineID returns id of the first goroutine in bs, 0 if not found
+func parserGoroutineID(bs []byte) int64 {
+	g := parserGoroutineInStack(bs)
+	if g == "" {
+		return 0
+	}
+	id, _ := strconv.ParseInt(strings.TrimPrefix(g, "goroutine "), 10, 64)
+	return id
+}
+
+// getGoExitInfo returns GoInfo of the exited goroutine and the error who will cancel group
+// err is the error returned by goroutine, see GoErr
+func getGoExitInfo(fi FuncInfo, panicValue any, err error) (GoInfo, error) {
 	ei := GoInfo{FuncInfo: fi, ExitTime: time.Now()}
 	if panicValue != nil {
-		st := stack(5)
-		gid := parserGoroutineInStack(st)
-		tail = gid + fmt.Sprintf(": panic(%v) exit", panicValue)
-		ei.Panic, ei.PanicStack = panicValue, st
-	} else {
-		tail = "exit"
+		pe := newPanicError(fi, panicValue, panicFrames())
+		ei.Panic, ei.PanicStack, ei.PanicFrames = panicValue, pe.Stack, pe.Frames
+		return ei, pe
+	}
+	ei.Err = err
+	return ei, &GoExitError{FuncInfo: fi, Err: err}
+}
+
+// newPanicError must be called on the panicked goroutine
+func newPanicError(fi FuncInfo, panicValue any, frames []Frame) *PanicError {
+	id := curGoroutineID()
+	st := append([]byte("goroutine "+strconv.FormatInt(id, 10)+" [running]:\n"), formatFrames(frames)...)
+	return &PanicError{
+		FuncInfo:    fi,
+		Value:       panicValue,
+		Stack:       st,
+		Frames:      frames,
+		GoroutineID: id,
 	}
-	return ei, errors.New(fi.String() + ": " + tail)
 }
 
 func isContextDone(ctx context.Context) bool {
@@ -126,3 +164,18 @@ func isContextDone(ctx context.Context) bool {
 		return false
 	}
 }
+
+// sleepContext sleeps d. return false if ctx is done before d passed
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return !isContextDone(ctx)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
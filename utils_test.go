@@ -15,8 +15,8 @@ func TestParserFuncInfo(t *testing.T) {
 		t.Fatal("FuncName not ff")
 	}
 
-	if fi.Line != 88 {
-		t.Fatal("Line not 88")
+	if fi.Line != 89 {
+		t.Fatal("Line not 89")
 	}
 }
 
@@ -62,35 +62,23 @@ func Test_wait(t *testing.T) {
 	wg.Wait()
 }
 
-func Test_stack(t *testing.T) {
-	bs := stack(4)
-	lines := bytes2lines(bs)
-	if len(lines) != 3 {
-		t.Fatal("stack size error. 3")
+func Test_parserGoroutineID(t *testing.T) {
+	if parserGoroutineID([]byte("goroutine 9111 [running]:")) != 9111 {
+		t.Fatal("not 9111")
 	}
-
-	bs = stack(0)
-	lines = bytes2lines(bs)
-	if len(lines) != 11 {
-		t.Fatal("stack size error. 11")
-	}
-
-	bs = stack(5)
-	lines = bytes2lines(bs)
-	if len(lines) != 11 {
-		t.Fatal("stack size error. 11")
+	if parserGoroutineID([]byte("abc")) != 0 {
+		t.Fatal("abc not 0")
 	}
+}
 
-	bs = stack(100)
-	lines = bytes2lines(bs)
-	if len(lines) != 11 {
-		t.Fatal("stack size error. 11")
+func Test_goroutineStacks(t *testing.T) {
+	id := curGoroutineID()
+	if id == 0 {
+		t.Fatal("id is 0")
 	}
-
-	bs = stack(2)
-	lines = bytes2lines(bs)
-	if len(lines) != 7 {
-		t.Fatal("stack size error. 7")
+	st, ok := goroutineStacks()[id]
+	if !ok || !strings.Contains(string(st), "Test_goroutineStacks") {
+		t.Fatal("stack of current goroutine not found")
 	}
 }
 
@@ -0,0 +1,37 @@
+package gogroup
+
+import (
+	"time"
+)
+
+type watchdog struct {
+	after  time.Duration
+	report func(Straggler)
+}
+
+// SetWatchdog
+// after Group is canceled, every goroutine still running after `after` is reported to report,
+// with its FuncInfo and current stack. it helps to find goroutines who ignore ctx.Done().
+// for goroutines started by GoWithPhase, `after` is counted from the time their phase is canceled.
+// report is called once for each of them, one by one in a goroutine of the watchdog, so it should not block.
+// after <= 0 disables the watchdog. should be called before Group is canceled
+func (g *Group) SetWatchdog(after time.Duration, report func(Straggler)) {
+	if after <= 0 || report == nil {
+		g.watchdog.Store(nil)
+		return
+	}
+	g.watchdog.Store(&watchdog{after: after, report: report})
+}
+
+// startWatchdog is called once when phase n is canceled, phase 0 is canceled with g
+func (g *Group) startWatchdog(n int) {
+	wd := g.watchdog.Load()
+	if wd == nil {
+		return
+	}
+	time.AfterFunc(wd.after, func() {
+		for _, s := range g.stragglers(n) {
+			wd.report(s)
+		}
+	})
+}
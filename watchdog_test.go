@@ -0,0 +1,83 @@
+package gogroup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupSetWatchdog(t *testing.T) {
+	var g Group
+	var mu sync.Mutex
+	var reported []Straggler
+	g.SetWatchdog(time.Millisecond*50, func(s Straggler) {
+		mu.Lock()
+		reported = append(reported, s)
+		mu.Unlock()
+	})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.Go(ignoreCancel)
+	g.CancelAndWait(fmt.Errorf("stop"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 {
+		t.Fatalf("%d reported, not 1", len(reported))
+	}
+	if !strings.HasSuffix(reported[0].FuncInfo.FuncName, ".ignoreCancel") {
+		t.Fatal("reported not ignoreCancel", reported[0].FuncInfo)
+	}
+	if !strings.Contains(string(reported[0].Stack), "ignoreCancel") {
+		t.Fatal("stack not contain ignoreCancel", string(reported[0].Stack))
+	}
+}
+
+func TestGroupSetWatchdogNoStraggler(t *testing.T) {
+	var g Group
+	g.SetWatchdog(time.Millisecond*10, func(s Straggler) {
+		t.Error("should not report", s.FuncInfo)
+	})
+	g.Go(func(ctx context.Context) {
+		<-ctx.Done()
+	})
+	g.CancelAndWait(fmt.Errorf("stop"))
+	time.Sleep(time.Millisecond * 30)
+}
+
+func slowExitPhase0(ctx context.Context) {
+	<-ctx.Done()
+	time.Sleep(time.Millisecond * 120)
+}
+
+func slowExitPhase1(ctx context.Context) {
+	<-ctx.Done()
+	time.Sleep(time.Millisecond * 120)
+}
+
+func TestGroupSetWatchdogPhase(t *testing.T) {
+	var g Group
+	var mu sync.Mutex
+	var reported []string
+	g.SetWatchdog(time.Millisecond*50, func(s Straggler) {
+		mu.Lock()
+		reported = append(reported, s.FuncInfo.FuncName)
+		mu.Unlock()
+	})
+	g.GoWithPhase(slowExitPhase0, 0)
+	g.GoWithPhase(slowExitPhase1, 1)
+	g.CancelAndWait(fmt.Errorf("stop"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 2 {
+		t.Fatalf("%d reported, not 2", len(reported))
+	}
+	if !strings.HasSuffix(reported[0], ".slowExitPhase0") || !strings.HasSuffix(reported[1], ".slowExitPhase1") {
+		t.Fatal("reported not right", reported)
+	}
+}